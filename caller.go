@@ -0,0 +1,131 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CallerFormat defines the format of the caller field.
+type CallerFormat uint8
+
+const (
+	// ShortCaller formats the caller as the base name of the file and the line, e.g. "handler.go:16".
+	ShortCaller CallerFormat = iota
+	// PackageCaller formats the caller as the package name, the base name of the file
+	// and the line, e.g. "auth/handler.go:16".
+	PackageCaller
+	// ModuleCaller formats the caller as the path of the file relative to its module
+	// and the line, e.g. "api/auth/handler.go:16" or "cmd/server/main.go:16".
+	ModuleCaller
+	// FullCaller formats the caller as the full path of the file and the line,
+	// e.g. "/src/api/auth/handler.go:16".
+	FullCaller
+	// FuncCaller formats the caller as the function name, e.g. "auth.(*Handler).ServeHTTP".
+	FuncCaller
+)
+
+var (
+	modulesOnce sync.Once
+	modules     []string
+	mainModule  string
+	mainPaths   sync.Map
+)
+
+// loadModules loads the paths of the modules of the build.
+func loadModules() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	if len(info.Main.Path) > 0 {
+		mainModule = info.Main.Path
+		modules = append(modules, info.Main.Path)
+	}
+	for _, dep := range info.Deps {
+		modules = append(modules, dep.Path)
+	}
+}
+
+// modulePath returns the path of the file relative to the module of the package.
+func modulePath(pkg, file string) string {
+	modulesOnce.Do(loadModules)
+	if pkg == "main" {
+		return mainPath(file)
+	}
+	var module string
+	for _, m := range modules {
+		if len(m) > len(module) && (pkg == m || strings.HasPrefix(pkg, m+"/")) {
+			module = m
+		}
+	}
+	base := path.Base(file)
+	if len(module) == 0 {
+		return pkg + "/" + base
+	}
+	if pkg == module {
+		return base
+	}
+	return pkg[len(module)+1:] + "/" + base
+}
+
+// mainPath returns the path of the file of a main package relative to the
+// directory of its module, i.e. the nearest directory containing a go.mod file,
+// or the full path if the directory is not found.
+func mainPath(file string) string {
+	if v, ok := mainPaths.Load(file); ok {
+		return v.(string)
+	}
+	p := file
+	if len(mainModule) > 0 && strings.HasPrefix(file, mainModule+"/") {
+		// the file is trimmed by -trimpath
+		p = file[len(mainModule)+1:]
+	} else {
+		for dir := path.Dir(file); dir != path.Dir(dir); dir = path.Dir(dir) {
+			if _, err := os.Stat(path.Join(dir, "go.mod")); err == nil {
+				p = file[len(dir)+1:]
+				break
+			}
+		}
+	}
+	mainPaths.Store(file, p)
+	return p
+}
+
+// funcName returns the function name without the directories of the package path.
+func funcName(function string) string {
+	if i := strings.LastIndexByte(function, '/'); i >= 0 {
+		return function[i+1:]
+	}
+	return function
+}
+
+// writeCaller writes the caller frame formatted by the format into the buffer.
+func writeCaller(buf *bytes.Buffer, frame runtime.Frame, format CallerFormat) {
+	switch format {
+	case PackageCaller:
+		buf.WriteString(path.Base(funcPackage(frame.Function)))
+		buf.WriteByte('/')
+		buf.WriteString(path.Base(frame.File))
+	case ModuleCaller:
+		buf.WriteString(modulePath(funcPackage(frame.Function), frame.File))
+	case FullCaller:
+		buf.WriteString(frame.File)
+	case FuncCaller:
+		buf.WriteString(funcName(frame.Function))
+		return
+	default:
+		buf.WriteString(path.Base(frame.File))
+	}
+	buf.WriteByte(':')
+	var b [20]byte
+	buf.Write(strconv.AppendInt(b[:0], int64(frame.Line), 10))
+}
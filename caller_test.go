@@ -0,0 +1,171 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestWriteCaller(t *testing.T) {
+	frame := runtime.Frame{
+		Function: "github.com/hslam/log/api/auth.(*Handler).ServeHTTP",
+		File:     "/src/log/api/auth/handler.go",
+		Line:     16,
+	}
+	cases := map[CallerFormat]string{
+		ShortCaller:   "handler.go:16",
+		PackageCaller: "auth/handler.go:16",
+		ModuleCaller:  "api/auth/handler.go:16",
+		FullCaller:    "/src/log/api/auth/handler.go:16",
+		FuncCaller:    "auth.(*Handler).ServeHTTP",
+	}
+	buf := bytes.NewBuffer(nil)
+	modulesOnce.Do(loadModules)
+	loaded := modules
+	t.Cleanup(func() { modules = loaded })
+	modules = []string{"github.com/hslam/log"}
+	for format, expect := range cases {
+		buf.Reset()
+		writeCaller(buf, frame, format)
+		if buf.String() != expect {
+			t.Errorf("error %s != %s", buf.String(), expect)
+		}
+	}
+	if p := modulePath("example.com/pkg", "/src/pkg/main.go"); p != "example.com/pkg/main.go" {
+		t.Error(p)
+	}
+	if p := modulePath("github.com/hslam/log", "/src/log/log.go"); p != "log.go" {
+		t.Error(p)
+	}
+}
+
+func TestSetCallerSkip(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.Info("HelloWorld")
+	if !strings.Contains(buf.String(), "[caller_test.go:") {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetCallerSkip(1)
+	l.Info("HelloWorld")
+	if !strings.Contains(buf.String(), "[testing.go:") {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetCallerSkip(0)
+	wrapper := l.AddCallerSkip(1)
+	helper := func() {
+		wrapper.Info("HelloWorld")
+	}
+	helper()
+	if !strings.Contains(buf.String(), "[caller_test.go:71]") || l.GetCallerSkip() != 0 || wrapper.GetCallerSkip() != 1 {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	nested := func() {
+		func() {
+			wrapper.AddCallerSkip(1).Info("HelloWorld")
+		}()
+	}
+	nested()
+	if !strings.Contains(buf.String(), "[caller_test.go:81]") {
+		t.Error(buf.String())
+	}
+}
+
+func TestCallerDepth(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetLevel(AllLevel)
+	l.SetTime(false)
+	l.SetStackLevel(OffLevel)
+	l.SetExitFunc(func(int) {})
+	l.Info("HelloWorld")
+	l.Infof("%s", "HelloWorld")
+	l.Infoln("HelloWorld")
+	l.Log(InfoLevel, "HelloWorld")
+	l.Fatal("HelloWorld")
+	l.Assert(false)
+	func() {
+		defer func() { recover() }()
+		l.Panic("HelloWorld")
+	}()
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "[caller_test.go:") {
+			t.Error(line)
+		}
+	}
+	defer ReplaceDefault(l)()
+	buf.Reset()
+	Info("HelloWorld")
+	Infof("%s", "HelloWorld")
+	Log(InfoLevel, "HelloWorld")
+	Fatal("HelloWorld")
+	Assertf(false, "%s", "HelloWorld")
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "[caller_test.go:") {
+			t.Error(line)
+		}
+	}
+}
+
+func TestModuleCallerMain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := ioutil.ReadFile("go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module probe\n\ngo 1.15\n\nrequire github.com/hslam/log v0.0.0\n\nreplace github.com/hslam/log => " + wd + "\n",
+		"go.sum": string(sum),
+		"mc/main.go": `package main
+
+import "github.com/hslam/log"
+
+func main() {
+	l := log.NewDeterministic()
+	l.SetCallerFormat(log.ModuleCaller)
+	l.Info("HelloWorld")
+}
+`,
+	}
+	for name, content := range files {
+		os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goBin, "run", "./mc")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Skip(err, string(out))
+	}
+	if !strings.Contains(string(out), "[mc/main.go:8]") {
+		t.Error(string(out))
+	}
+}
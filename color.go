@@ -0,0 +1,115 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// isTerminal reports whether the writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled reports whether the auto highlight is enabled for the writer.
+// A non-empty NO_COLOR disables the highlight, a FORCE_COLOR other than
+// "0" and "false" enables it, and TERM=dumb disables it. Otherwise the
+// highlight is enabled if the writer is a terminal.
+func colorEnabled(w io.Writer) bool {
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
+	}
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok && force != "0" && force != "false" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// SetAutoHighlight sets whether to enable the highlight field only when the output
+// is a terminal, honoring the NO_COLOR, FORCE_COLOR and TERM environment variables.
+func SetAutoHighlight(enable bool) {
+	Default().SetAutoHighlight(enable)
+}
+
+// SetAutoHighlight sets whether to enable the highlight field only when the output
+// is a terminal, honoring the NO_COLOR, FORCE_COLOR and TERM environment variables.
+// It has no effect if the highlight is enabled by SetHighlight.
+func (l *Logger) SetAutoHighlight(enable bool) {
+	l.autoHighlight = enable
+	l.init()
+}
+
+// writerSink implements the Sink interface that writes the encoded records to a writer.
+type writerSink struct {
+	mu sync.Mutex
+	l  *Logger
+	w  io.Writer
+	// terminal reports whether the highlight is enabled for the writer.
+	terminal bool
+}
+
+// Log writes the encoded record to the writer.
+func (s *writerSink) Log(r *Record) {
+	color := s.terminal && s.l.autoHighlight
+	enc := s.l.enc
+	if color {
+		enc = s.l.colorEnc
+	}
+	buf := newBuffer()
+	enc.Encode(buf, r)
+	b := buf.Bytes()
+	if !color {
+		b = stripColor(b)
+	}
+	s.mu.Lock()
+	s.w.Write(b)
+	s.mu.Unlock()
+	freeBuffer(buf)
+}
+
+// AddWriter adds a writer, e.g. a file, receiving the records in addition to the output.
+func AddWriter(w io.Writer) {
+	Default().AddWriter(w)
+}
+
+// AddWriter adds a writer, e.g. a file, receiving the records in addition to the
+// output. The records are encoded by the encoder of the logger, and they are only
+// highlighted in the auto highlight mode when the writer is a terminal, so that
+// the colors are stripped from the files.
+func (l *Logger) AddWriter(w io.Writer) {
+	l.AddSink(&writerSink{l: l, w: w, terminal: isTerminal(w) && colorEnabled(w)})
+}
+
+// stripColor removes the ANSI color sequences, i.e. ESC [ [0-9;]* m, from b.
+func stripColor(b []byte) []byte {
+	n := 0
+	for i := 0; i < len(b); i++ {
+		if b[i] == 27 && i+1 < len(b) && b[i+1] == '[' {
+			j := i + 2
+			for j < len(b) && (b[j] >= '0' && b[j] <= '9' || b[j] == ';') {
+				j++
+			}
+			if j < len(b) && b[j] == 'm' {
+				i = j
+				continue
+			}
+		}
+		b[n] = b[i]
+		n++
+	}
+	return b[:n]
+}
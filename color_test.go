@@ -0,0 +1,117 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	if len(value) > 0 {
+		os.Setenv(key, value)
+	} else {
+		os.Unsetenv(key)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+func TestColorEnabled(t *testing.T) {
+	setenv(t, "NO_COLOR", "")
+	setenv(t, "FORCE_COLOR", "")
+	setenv(t, "TERM", "xterm")
+	buf := bytes.NewBuffer(nil)
+	if colorEnabled(buf) {
+		t.Error("")
+	}
+	setenv(t, "FORCE_COLOR", "1")
+	if !colorEnabled(buf) {
+		t.Error("")
+	}
+	setenv(t, "FORCE_COLOR", "0")
+	if colorEnabled(buf) {
+		t.Error("")
+	}
+	setenv(t, "FORCE_COLOR", "1")
+	setenv(t, "NO_COLOR", "1")
+	if colorEnabled(buf) {
+		t.Error("")
+	}
+	setenv(t, "NO_COLOR", "")
+	setenv(t, "FORCE_COLOR", "")
+	setenv(t, "TERM", "dumb")
+	if colorEnabled(os.Stdout) {
+		t.Error("")
+	}
+}
+
+func TestSetAutoHighlight(t *testing.T) {
+	setenv(t, "NO_COLOR", "")
+	setenv(t, "FORCE_COLOR", "")
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetAutoHighlight(true)
+	l.SetOut(buf)
+	l.Info("HelloWorld")
+	if buf.String() != `[INFO] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	setenv(t, "FORCE_COLOR", "1")
+	l.SetOut(buf)
+	file := bytes.NewBuffer(nil)
+	l.AddWriter(file)
+	l.Named("api").Info("HelloWorld")
+	if buf.String() != string(cyan)+`[api] [INFO] ["HelloWorld"]`+string(reset)+"\n" {
+		t.Errorf("%q", buf.String())
+	}
+	if file.String() != `[api] [INFO] ["HelloWorld"]`+"\n" {
+		t.Errorf("%q", file.String())
+	}
+	file.Reset()
+	l.SetAutoHighlight(false)
+	l.SetHighlight(true)
+	l.Info("HelloWorld")
+	if file.String() != `[INFO] ["HelloWorld"]`+"\n" {
+		t.Errorf("%q", file.String())
+	}
+}
+
+func TestStripColor(t *testing.T) {
+	b := []byte(string(redBg) + "Hello" + string(reset) + "World" + "\x1b[")
+	if s := string(stripColor(b)); s != "HelloWorld\x1b[" {
+		t.Errorf("%q", s)
+	}
+	b = []byte("\x1b]0;title\x07Hello\x1b[1;31mWorld\x1b[2K name")
+	if s := string(stripColor(b)); s != "\x1b]0;title\x07HelloWorld\x1b[2K name" {
+		t.Errorf("%q", s)
+	}
+}
+
+func TestWriterSinkHighlight(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetOut(bytes.NewBuffer(nil))
+	l.AddSink(&writerSink{l: l, w: buf, terminal: true})
+	l.Info("HelloWorld")
+	if buf.String() != `[INFO] ["HelloWorld"]`+"\n" {
+		t.Errorf("%q", buf.String())
+	}
+	buf.Reset()
+	l.SetAutoHighlight(true)
+	l.Info("HelloWorld")
+	if buf.String() != string(cyan)+`[INFO] ["HelloWorld"]`+string(reset)+"\n" {
+		t.Errorf("%q", buf.String())
+	}
+}
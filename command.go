@@ -0,0 +1,234 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CommandOption defines the option of Command.
+type CommandOption func(*commandOptions)
+
+type commandOptions struct {
+	name    string
+	stdout  Level
+	stderr  Level
+	detect  bool
+	records bool
+}
+
+// CommandName sets the name of the child logger of the command.
+// The default is the base name of the path of the command.
+func CommandName(name string) CommandOption {
+	return func(o *commandOptions) {
+		o.name = name
+	}
+}
+
+// StdoutLevel sets the level of the lines of the standard output. The default is the info level.
+func StdoutLevel(level Level) CommandOption {
+	return func(o *commandOptions) {
+		o.stdout = level
+	}
+}
+
+// StderrLevel sets the level of the lines of the standard error. The default is the warn level.
+func StderrLevel(level Level) CommandOption {
+	return func(o *commandOptions) {
+		o.stderr = level
+	}
+}
+
+// DetectLevel detects the level of a line from the level markers in it,
+// e.g. "ERROR", "WARN" or "[E]".
+func DetectLevel() CommandOption {
+	return func(o *commandOptions) {
+		o.detect = true
+	}
+}
+
+// ParseRecords parses the lines in the bracketed text format of this package,
+// so that the level, the message and the fields of the lines are kept.
+func ParseRecords() CommandOption {
+	return func(o *commandOptions) {
+		o.records = true
+	}
+}
+
+// parse returns the level, the message and the fields of the line.
+func (o *commandOptions) parse(line string, level Level) (Level, string, []Field) {
+	if o.records {
+		if l, msg, fields, ok := parseRecord(line); ok {
+			return l, msg, fields
+		}
+	}
+	if o.detect {
+		level = detectLevel(line, level)
+	}
+	return level, line, nil
+}
+
+// commandWriters closes the writers of the standard output and error.
+type commandWriters [2]*lineWriter
+
+func (w commandWriters) Close() error {
+	w[0].Close()
+	return w[1].Close()
+}
+
+// Command logs the standard output and error of the command.
+func Command(cmd *exec.Cmd, opts ...CommandOption) io.Closer {
+	return Default().Command(cmd, opts...)
+}
+
+// Command sets the standard output and error of the command to writers logging
+// each line with a child logger named after the command, e.g. [api.worker].
+// The writers already set to the command still receive the output. The lines
+// carry neither a caller nor a call stack. Close the returned closer after the
+// command is waited to log the incomplete last lines.
+func (l *Logger) Command(cmd *exec.Cmd, opts ...CommandOption) io.Closer {
+	o := &commandOptions{name: filepath.Base(cmd.Path), stdout: InfoLevel, stderr: WarnLevel}
+	for _, opt := range opts {
+		opt(o)
+	}
+	c := l.Named(o.name)
+	c.SetLine(false)
+	c.SetStackLevel(OffLevel)
+	stdout := &lineWriter{l: c, level: o.stdout}
+	stderr := &lineWriter{l: c, level: o.stderr}
+	if o.detect || o.records {
+		stdout.parse = o.parse
+		stderr.parse = o.parse
+	}
+	cmd.Stdout = teeWriter(cmd.Stdout, stdout)
+	cmd.Stderr = teeWriter(cmd.Stderr, stderr)
+	return commandWriters{stdout, stderr}
+}
+
+// teeWriter returns a writer writing to both the existing writer and w, or w
+// if there is no existing writer.
+func teeWriter(existing, w io.Writer) io.Writer {
+	if existing == nil {
+		return w
+	}
+	return io.MultiWriter(existing, w)
+}
+
+// levelAliases are the markers of the levels used by other programs in
+// addition to the names of the levels.
+var levelAliases = map[string]Level{
+	"WARNING":  WarnLevel,
+	"ERR":      ErrorLevel,
+	"CRITICAL": FatalLevel,
+}
+
+// markerLevel returns the level of the marker, i.e. the name of a registered
+// level or an alias. The short name is matched if short is true.
+func markerLevel(marker string, short bool) (Level, bool) {
+	s := loadLevels()
+	for _, level := range s.order {
+		if level == AllLevel || level == OffLevel {
+			continue
+		}
+		name := s.infos[level].name
+		if short {
+			name = s.infos[level].short
+		}
+		if marker == name {
+			return level, true
+		}
+	}
+	if !short {
+		level, ok := levelAliases[marker]
+		return level, ok
+	}
+	return 0, false
+}
+
+// detectLevel returns the level of the first marker in the line, i.e. an upper
+// case word such as "ERROR" or a short level in brackets such as "[E]".
+func detectLevel(line string, def Level) Level {
+	for i := 0; i < len(line); {
+		if line[i] == '[' {
+			if j := strings.IndexByte(line[i+1:], ']'); j > 0 {
+				if level, ok := markerLevel(line[i+1:i+1+j], true); ok {
+					return level
+				}
+			}
+		}
+		if !isUpper(line[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && isUpper(line[j]) {
+			j++
+		}
+		if (i == 0 || !isLetter(line[i-1])) && (j == len(line) || !isLetter(line[j])) {
+			if level, ok := markerLevel(line[i:j], false); ok {
+				return level
+			}
+		}
+		i = j
+	}
+	return def
+}
+
+func isUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isLetter(c byte) bool {
+	return isUpper(c) || 'a' <= c && c <= 'z'
+}
+
+// parseRecord parses the line in the bracketed text format of this package,
+// e.g. `[api] [2023/05/13 18:21:51.183 +08:00] [INFO] [main.go:16] ["HelloWorld"] [key=1024]`.
+// The line must contain a level and a message.
+func parseRecord(line string) (level Level, msg string, fields []Field, ok bool) {
+	s := line
+	found := false
+	for {
+		s = strings.TrimLeft(s, " ")
+		if strings.HasPrefix(s, `["`) {
+			end := strings.Index(s, `"] `)
+			if end < 0 {
+				if !strings.HasSuffix(s, `"]`) {
+					return
+				}
+				end = len(s) - 2
+			}
+			msg, s = s[2:end], s[end+2:]
+			break
+		}
+		end := strings.IndexByte(s, ']')
+		if !strings.HasPrefix(s, "[") || end < 0 {
+			return
+		}
+		if !found {
+			level, found = ParseLevel(s[1:end])
+		}
+		s = s[end+1:]
+	}
+	if !found {
+		return
+	}
+	for {
+		s = strings.TrimLeft(s, " ")
+		end := strings.IndexByte(s, ']')
+		if !strings.HasPrefix(s, "[") || strings.HasPrefix(s, "[stack=") || end < 0 {
+			break
+		}
+		eq := strings.IndexByte(s[:end], '=')
+		if eq < 0 {
+			break
+		}
+		fields = append(fields, String(s[1:eq], s[eq+1:end]))
+		s = s[end+1:]
+	}
+	return level, msg, fields, true
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip(err)
+	}
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLevel(DebugLevel)
+	l.SetOut(buf)
+	cmd := exec.Command("sh", "-c", `echo hello; echo "WARN: disk" 1>&2; printf "last"`)
+	closer := l.Command(cmd, CommandName("child"), DetectLevel())
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	closer.Close()
+	for _, line := range []string{
+		`[child] [INFO] ["hello"]` + "\n",
+		`[child] [WARN] ["WARN: disk"]` + "\n",
+		`[child] [INFO] ["last"]` + "\n",
+	} {
+		if !bytes.Contains(buf.Bytes(), []byte(line)) {
+			t.Errorf("%s not in %s", line, buf.String())
+		}
+	}
+	buf.Reset()
+	cmd = exec.Command("sh", "-c", `echo '[2023/05/13 18:21:51.183 +08:00] [E] [main.go:16] ["failed"] [key=1024]'; echo '[DEBUG] ["HelloWorld"]'`)
+	closer = l.Command(cmd, ParseRecords())
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	closer.Close()
+	expect := `[sh] [ERROR] ["failed"] [key=1024]` + "\n" + `[sh] [DEBUG] ["HelloWorld"]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+}
+
+func TestDetectLevel(t *testing.T) {
+	cases := map[string]Level{
+		"ERROR: failed":         ErrorLevel,
+		"2023-05-13 WARN disk":  WarnLevel,
+		"[E] failed":            ErrorLevel,
+		"Errors are not levels": InfoLevel,
+		"INFORMATION":           InfoLevel,
+		"level=DEBUG msg=hello": DebugLevel,
+		"":                      InfoLevel,
+	}
+	for line, level := range cases {
+		if l := detectLevel(line, InfoLevel); l != level {
+			t.Errorf("%q: %d != %d", line, l, level)
+		}
+	}
+	restoreLevels(t)
+	audit, err := RegisterLevel(NoticeLevel, "AUDIT", "AU", green)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"AUDIT: login", "[AU] login"} {
+		if l := detectLevel(line, InfoLevel); l != audit {
+			t.Errorf("%q: %d != %d", line, l, audit)
+		}
+	}
+}
+
+func TestCommandWriters(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip(err)
+	}
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetOut(buf)
+	stdout := bytes.NewBuffer(nil)
+	cmd := exec.Command("sh", "-c", "echo hello")
+	cmd.Stdout = stdout
+	closer := l.Command(cmd)
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	closer.Close()
+	if stdout.String() != "hello\n" || buf.String() != `[sh] [INFO] ["hello"]`+"\n" {
+		t.Errorf("%q %q", stdout.String(), buf.String())
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	level, msg, fields, ok := parseRecord(`[api] [INFO] [main.go:16] ["Hello World"] [a=1] [b=x y] [stack="main.main"]`)
+	if !ok || level != InfoLevel || msg != "Hello World" || len(fields) != 2 || fields[1].Str != "x y" {
+		t.Error(level, msg, fields, ok)
+	}
+	for _, line := range []string{"", "hello", `[INFO] hello`, `["hello"]`, `[api] [INFO] ["hello`} {
+		if _, _, _, ok := parseRecord(line); ok {
+			t.Error(line)
+		}
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var recordPool sync.Pool
+
+// Record defines a log record.
+type Record struct {
+	// Time is the time when the record is created. It is zero if the time field is disabled.
+	Time time.Time
+	// Level is the level of the record.
+	Level Level
+	// Prefix is the prefix of the logger.
+	Prefix string
+	// Caller is the frame of the caller. It is zero if the line field is disabled.
+	Caller runtime.Frame
+	// Message is the log message.
+	Message string
+	// Stack is the call stack. It is empty below the error level.
+	Stack []runtime.Frame
+	// Fields are the key/value pairs of the record.
+	Fields []Field
+
+	// fields is the reused storage of Fields, so that the fields of the
+	// calls do not escape to the heap.
+	fields []Field
+}
+
+// Clone returns a copy of the record that can be retained after logging.
+func (r *Record) Clone() *Record {
+	c := *r
+	c.fields = nil
+	if r.Stack != nil {
+		c.Stack = make([]runtime.Frame, len(r.Stack))
+		copy(c.Stack, r.Stack)
+	}
+	if r.Fields != nil {
+		c.Fields = make([]Field, len(r.Fields))
+		copy(c.Fields, r.Fields)
+	}
+	return &c
+}
+
+// Sink defines the interface that receives the records in addition to the output.
+type Sink interface {
+	// Log handles the record. The record is only valid during the call,
+	// Clone it to retain it.
+	Log(r *Record)
+}
+
+// newRecord returns a new record.
+func newRecord() *Record {
+	if v := recordPool.Get(); v != nil {
+		r := v.(*Record)
+		return r
+	}
+	return &Record{}
+}
+
+// maxRecordFields is the max capacity of the reused storage of the fields.
+const maxRecordFields = 64
+
+// freeRecord frees the record.
+func freeRecord(r *Record) {
+	fields := r.fields
+	for i := range fields {
+		fields[i] = Field{}
+	}
+	if cap(fields) > maxRecordFields {
+		fields = nil
+	}
+	*r = Record{fields: fields[:0]}
+	recordPool.Put(r)
+}
+
+// Encoder defines the interface to encode a record.
+//
+// The record is only valid during the call of Encode.
+type Encoder interface {
+	// Encode writes the record as a line into the buffer.
+	Encode(buf *bytes.Buffer, r *Record)
+}
+
+// TextEncoder implements the Encoder interface with the bracketed text layout.
+type TextEncoder struct {
+	// ShortLevel enables the short level name.
+	ShortLevel bool
+	// Highlight enables the highlight color.
+	Highlight bool
+	// TimeFormat is the layout of the time field. The default is DefaultTimeFormat.
+	TimeFormat string
+	// Layout is the template of the line. The placeholders {prefix}, {time}, {level},
+	// {caller}, {msg}, {fields} and {stack} are replaced with the fields of the record.
+	// The default is DefaultLayout.
+	Layout string
+	// NoBrackets disables the brackets around the fields.
+	NoBrackets bool
+	// Multiline writes the call stack in the following lines instead of the {stack}
+	// field, whether or not the layout has the {stack} field.
+	Multiline bool
+	// CallerFormat is the format of the caller field.
+	CallerFormat CallerFormat
+
+	parsed atomic.Value
+}
+
+// NewTextEncoder returns a new text encoder.
+func NewTextEncoder() *TextEncoder {
+	return &TextEncoder{}
+}
+
+const (
+	// DefaultTimeFormat is the default layout of the time field.
+	DefaultTimeFormat = "2006/01/02 15:04:05.000 -07:00"
+	// UnixTimeFormat formats the time as the number of seconds since the Unix epoch.
+	UnixTimeFormat = "unix"
+	// UnixMilliTimeFormat formats the time as the number of milliseconds since the Unix epoch.
+	UnixMilliTimeFormat = "unixmilli"
+	// UnixNanoTimeFormat formats the time as the number of nanoseconds since the Unix epoch.
+	UnixNanoTimeFormat = "unixnano"
+)
+
+// appendTime appends the time formatted by the layout to b.
+func appendTime(b []byte, t time.Time, layout string) []byte {
+	switch layout {
+	case "", DefaultTimeFormat:
+		return appendDefaultTime(b, t)
+	case UnixTimeFormat:
+		return strconv.AppendInt(b, t.Unix(), 10)
+	case UnixMilliTimeFormat:
+		return strconv.AppendInt(b, t.UnixNano()/int64(time.Millisecond), 10)
+	case UnixNanoTimeFormat:
+		return strconv.AppendInt(b, t.UnixNano(), 10)
+	}
+	return t.AppendFormat(b, layout)
+}
+
+// appendDigits appends the decimal digits of n padded with zeros to the width.
+func appendDigits(b []byte, n, width int) []byte {
+	var d [8]byte
+	for i := width - 1; i >= 0; i-- {
+		d[i] = byte('0' + n%10)
+		n /= 10
+	}
+	return append(b, d[:width]...)
+}
+
+// appendDefaultTime appends the time formatted by DefaultTimeFormat to b.
+func appendDefaultTime(b []byte, t time.Time) []byte {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	_, offset := t.Zone()
+	b = appendDigits(b, year, 4)
+	b = append(b, '/')
+	b = appendDigits(b, int(month), 2)
+	b = append(b, '/')
+	b = appendDigits(b, day, 2)
+	b = append(b, ' ')
+	b = appendDigits(b, hour, 2)
+	b = append(b, ':')
+	b = appendDigits(b, min, 2)
+	b = append(b, ':')
+	b = appendDigits(b, sec, 2)
+	b = append(b, '.')
+	b = appendDigits(b, t.Nanosecond()/int(time.Millisecond), 3)
+	b = append(b, ' ')
+	if offset < 0 {
+		b = append(b, '-')
+		offset = -offset
+	} else {
+		b = append(b, '+')
+	}
+	offset /= 60
+	b = appendDigits(b, offset/60, 2)
+	b = append(b, ':')
+	return appendDigits(b, offset%60, 2)
+}
+
+// DefaultLayout is the default layout of the text encoder.
+const DefaultLayout = "{prefix} {time} {level} {caller} {msg} {fields} {stack}"
+
+// fields of the layout
+const (
+	prefixField = iota
+	timeField
+	levelField
+	callerField
+	msgField
+	fieldsField
+	stackField
+)
+
+var layoutFields = map[string]int{
+	"{prefix}": prefixField,
+	"{time}":   timeField,
+	"{level}":  levelField,
+	"{caller}": callerField,
+	"{msg}":    msgField,
+	"{fields}": fieldsField,
+	"{stack}":  stackField,
+}
+
+// layoutSegment defines a field of the layout with the text before it.
+type layoutSegment struct {
+	text  string
+	field int
+	// sep reports whether the text is only whitespace separating the fields.
+	sep bool
+}
+
+// textLayout defines a parsed layout.
+type textLayout struct {
+	layout   string
+	segments []layoutSegment
+	trailer  string
+}
+
+// parseLayout parses the layout. Unknown placeholders are kept as text.
+func parseLayout(layout string) *textLayout {
+	t := &textLayout{layout: layout}
+	var text string
+	s := layout
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			break
+		}
+		if field, ok := layoutFields[s[i:i+j+1]]; ok {
+			sep := text + s[:i]
+			t.segments = append(t.segments, layoutSegment{sep, field, len(strings.TrimSpace(sep)) == 0})
+			text = ""
+		} else {
+			text += s[:i+j+1]
+		}
+		s = s[i+j+1:]
+	}
+	t.trailer = text + s
+	return t
+}
+
+// layout returns the parsed layout of the encoder.
+func (e *TextEncoder) layout() *textLayout {
+	layout := e.Layout
+	if len(layout) == 0 {
+		layout = DefaultLayout
+	}
+	if v := e.parsed.Load(); v != nil {
+		if t := v.(*textLayout); t.layout == layout {
+			return t
+		}
+	}
+	t := parseLayout(layout)
+	e.parsed.Store(t)
+	return t
+}
+
+// empty returns whether the field of the record is empty.
+func (r *Record) empty(field int) bool {
+	switch field {
+	case prefixField:
+		return len(r.Prefix) == 0
+	case timeField:
+		return r.Time.IsZero()
+	case callerField:
+		return len(r.Caller.File) == 0
+	case fieldsField:
+		return len(r.Fields) == 0
+	case stackField:
+		return len(r.Stack) == 0
+	}
+	return false
+}
+
+const (
+	newline = "\\n"
+	tab     = "\\n\\t"
+)
+
+// writeFrame writes the function and the file:line of the frame separated by sep.
+func writeFrame(buf *bytes.Buffer, frame runtime.Frame, sep string) {
+	var b [20]byte
+	buf.WriteString(frame.Function)
+	buf.WriteString(sep)
+	buf.WriteString(frame.File)
+	buf.WriteByte(':')
+	buf.Write(strconv.AppendInt(b[:0], int64(frame.Line), 10))
+}
+
+// Encode writes the record as a line into the buffer.
+//
+// The literal text of the layout is written as it is, while the whitespace
+// separating two fields is only written between the fields that are not empty.
+func (e *TextEncoder) Encode(buf *bytes.Buffer, r *Record) {
+	color := r.Level.color()
+	highlight := e.Highlight && len(color) > 0
+	if highlight {
+		buf.Write(color)
+	}
+	t := e.layout()
+	written := false
+	for _, seg := range t.segments {
+		empty := r.empty(seg.field) || seg.field == stackField && e.Multiline
+		if !seg.sep {
+			buf.WriteString(seg.text)
+			written = true
+		} else if written && !empty {
+			buf.WriteString(seg.text)
+		}
+		if empty {
+			continue
+		}
+		written = true
+		e.encodeField(buf, r, seg.field)
+	}
+	buf.WriteString(t.trailer)
+	if e.Multiline {
+		for _, frame := range r.Stack {
+			buf.WriteByte('\n')
+			writeFrame(buf, frame, "\n\t")
+		}
+	}
+	if highlight {
+		buf.Write(reset)
+	}
+	buf.WriteByte('\n')
+}
+
+// encodeField writes the field of the record into the buffer.
+func (e *TextEncoder) encodeField(buf *bytes.Buffer, r *Record, field int) {
+	switch field {
+	case prefixField:
+		e.open(buf)
+		buf.WriteString(r.Prefix)
+		e.close(buf)
+	case timeField:
+		var b [64]byte
+		e.open(buf)
+		buf.Write(appendTime(b[:0], r.Time, e.TimeFormat))
+		e.close(buf)
+	case levelField:
+		e.open(buf)
+		if e.ShortLevel {
+			buf.WriteString(r.Level.ShortString())
+		} else {
+			buf.WriteString(r.Level.String())
+		}
+		e.close(buf)
+	case callerField:
+		e.open(buf)
+		writeCaller(buf, r.Caller, e.CallerFormat)
+		e.close(buf)
+	case msgField:
+		e.open(buf)
+		e.quote(buf)
+		buf.WriteString(r.Message)
+		e.quote(buf)
+		e.close(buf)
+	case fieldsField:
+		for i, f := range r.Fields {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			e.open(buf)
+			buf.WriteString(f.Key)
+			buf.WriteByte('=')
+			e.writeValue(buf, f)
+			e.close(buf)
+		}
+	case stackField:
+		e.open(buf)
+		buf.WriteString("stack=\"")
+		for i, frame := range r.Stack {
+			if i > 0 {
+				buf.WriteString(newline)
+			}
+			writeFrame(buf, frame, tab)
+		}
+		buf.WriteByte('"')
+		e.close(buf)
+	}
+}
+
+func (e *TextEncoder) open(buf *bytes.Buffer) {
+	if !e.NoBrackets {
+		buf.WriteByte('[')
+	}
+}
+
+func (e *TextEncoder) close(buf *bytes.Buffer) {
+	if !e.NoBrackets {
+		buf.WriteByte(']')
+	}
+}
+
+func (e *TextEncoder) quote(buf *bytes.Buffer) {
+	if !e.NoBrackets {
+		buf.WriteByte('"')
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextEncoder(t *testing.T) {
+	r := &Record{
+		Time:    time.Date(2023, 5, 13, 18, 21, 51, 183000000, time.UTC),
+		Level:   ErrorLevel,
+		Prefix:  "LogPrefix",
+		Caller:  runtime.Frame{File: "/src/main.go", Line: 16},
+		Message: "HelloWorld",
+		Stack:   []runtime.Frame{{Function: "main.main", File: "/src/main.go", Line: 16}},
+		Fields:  []Field{F("key", 1024)},
+	}
+	buf := bytes.NewBuffer(nil)
+	NewTextEncoder().Encode(buf, r)
+	expect := `[LogPrefix] [2023/05/13 18:21:51.183 +00:00] [ERROR] [main.go:16] ["HelloWorld"] [key=1024] [stack="main.main\n\t/src/main.go:16"]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	(&TextEncoder{ShortLevel: true, Highlight: true}).Encode(buf, &Record{Level: InfoLevel, Message: "HelloWorld"})
+	expect = string(cyan) + `[I] ["HelloWorld"]` + string(reset) + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %q != %q", buf.String(), expect)
+	}
+}
+
+type messageEncoder struct{}
+
+func (e messageEncoder) Encode(buf *bytes.Buffer, r *Record) {
+	buf.WriteString(r.Level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(r.Message)
+	for _, f := range r.Fields {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+	}
+	buf.WriteByte('\n')
+}
+
+func TestSetEncoder(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetEncoder(messageEncoder{})
+	l.Info(1024, F("key", "value"), "HelloWorld")
+	l.Infof("%d %s", 1024, "HelloWorld", F("key", "value"))
+	if buf.String() != "INFO 1024HelloWorld key\nINFO 1024 HelloWorld key\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetEncoder(nil)
+	l.Info("HelloWorld")
+	if !strings.HasSuffix(buf.String(), `["HelloWorld"]`+"\n") {
+		t.Error(buf.String())
+	}
+}
+
+func TestTextEncoderLayout(t *testing.T) {
+	r := &Record{
+		Time:    time.Date(2023, 5, 13, 18, 21, 51, 183000000, time.UTC),
+		Level:   WarnLevel,
+		Caller:  runtime.Frame{File: "/src/main.go", Line: 16},
+		Message: "HelloWorld",
+		Fields:  []Field{F("a", 1), F("b", true)},
+	}
+	buf := bytes.NewBuffer(nil)
+	e := &TextEncoder{Layout: "{time} {level} {prefix} {caller} {msg} {fields}", TimeFormat: UnixTimeFormat}
+	e.Encode(buf, r)
+	expect := `[1684002111] [WARN] [main.go:16] ["HelloWorld"] [a=1] [b=true]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	e.NoBrackets = true
+	e.Layout = "{level}|{prefix}|{msg} ({fields}) {unknown}"
+	r.Fields = nil
+	e.Encode(buf, r)
+	expect = "WARN||HelloWorld () {unknown}\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	e.Layout = "<{prefix}> {msg}"
+	e.Encode(buf, r)
+	expect = "<> HelloWorld\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	e.Multiline = true
+	r.Stack = []runtime.Frame{{Function: "main.main", File: "/src/main.go", Line: 16}}
+	e.Encode(buf, r)
+	expect = "<> HelloWorld\nmain.main\n\t/src/main.go:16\n"
+	if buf.String() != expect {
+		t.Errorf("error %q != %q", buf.String(), expect)
+	}
+}
+
+func TestTextEncoderAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are counted without the race detector")
+	}
+	r := &Record{
+		Time:    time.Now(),
+		Level:   ErrorLevel,
+		Prefix:  "LogPrefix",
+		Caller:  runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 16},
+		Message: "HelloWorld",
+		Stack:   []runtime.Frame{{Function: "main.main", File: "/src/main.go", Line: 16}},
+		Fields:  []Field{String("string", "HelloWorld"), Int("int", 1024), F("any", 1024)},
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, 4096))
+	e := &TextEncoder{CallerFormat: PackageCaller}
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		e.Encode(buf, r)
+	})
+	if allocs > 0 {
+		t.Errorf("error %v allocs", allocs)
+	}
+}
+
+// benchRecord returns the record of the encoder benchmarks, with a call stack
+// of three frames if stack is true.
+func benchRecord(stack bool) *Record {
+	r := &Record{
+		Time:    time.Now(),
+		Level:   InfoLevel,
+		Caller:  runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 16},
+		Message: "HelloWorld",
+	}
+	if stack {
+		r.Level = ErrorLevel
+		for i := 0; i < 3; i++ {
+			r.Stack = append(r.Stack, runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 16})
+		}
+	}
+	return r
+}
+
+// fmtEncode encodes the record the way the decorators replaced by the
+// TextEncoder did, with fmt for the caller and a call stack joined from the
+// frames formatted by fmt.Sprintf. It is the baseline of the benchmarks.
+func fmtEncode(buf *bytes.Buffer, r *Record) {
+	var b [64]byte
+	buf.Write(r.Time.AppendFormat(b[:0], "[2006/01/02 15:04:05.000 -07:00] "))
+	buf.WriteString("[" + r.Level.String() + "] ")
+	fmt.Fprintf(buf, "[%s:%d] ", path.Base(r.Caller.File), r.Caller.Line)
+	buf.WriteString(`["`)
+	buf.WriteString(r.Message)
+	buf.WriteString(`"]`)
+	if len(r.Stack) > 0 {
+		var stack []string
+		for _, frame := range r.Stack {
+			stack = append(stack, fmt.Sprintf("%s\\n\\t%s:%d", frame.Function, frame.File, frame.Line))
+		}
+		fmt.Fprintf(buf, " [stack=\"%s\"]", strings.Join(stack, "\\n"))
+	}
+	fmt.Fprintln(buf)
+}
+
+func TestFmtEncode(t *testing.T) {
+	for _, stack := range []bool{false, true} {
+		r := benchRecord(stack)
+		buf, expect := bytes.NewBuffer(nil), bytes.NewBuffer(nil)
+		fmtEncode(buf, r)
+		NewTextEncoder().Encode(expect, r)
+		if buf.String() != expect.String() {
+			t.Errorf("error %s != %s", buf.String(), expect.String())
+		}
+	}
+}
+
+func benchmarkEncode(b *testing.B, r *Record, encode func(buf *bytes.Buffer, r *Record)) {
+	buf := bytes.NewBuffer(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		encode(buf, r)
+	}
+}
+
+func BenchmarkTextEncoder(b *testing.B) {
+	benchmarkEncode(b, benchRecord(false), NewTextEncoder().Encode)
+}
+
+func BenchmarkFmtEncoder(b *testing.B) {
+	benchmarkEncode(b, benchRecord(false), fmtEncode)
+}
+
+func BenchmarkTextEncoderStack(b *testing.B) {
+	benchmarkEncode(b, benchRecord(true), NewTextEncoder().Encode)
+}
+
+func BenchmarkFmtEncoderStack(b *testing.B) {
+	benchmarkEncode(b, benchRecord(true), fmtEncode)
+}
+
+func TestAppendDefaultTime(t *testing.T) {
+	zones := []*time.Location{time.UTC, time.FixedZone("A", 8*3600), time.FixedZone("B", -(3*3600 + 30*60))}
+	for _, zone := range zones {
+		now := time.Date(2023, 5, 3, 8, 1, 5, 7000000, zone)
+		if s := string(appendDefaultTime(nil, now)); s != now.Format(DefaultTimeFormat) {
+			t.Errorf("error %s != %s", s, now.Format(DefaultTimeFormat))
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log_test
+
+import (
+	"github.com/hslam/log"
+)
+
+func ExampleNewDeterministic() {
+	logger := log.NewDeterministic()
+	logger.SetPrefix("LogPrefix")
+	logger.SetLevel(log.AllLevel)
+	logger.Infof("%d %s %t", 1024, "HelloWorld", true)
+	logger.Warn("HelloWorld", log.F("key", 1024))
+	logger.Errorln(1024, "HelloWorld", true)
+	// Output:
+	// [LogPrefix] [INFO] [example_test.go:14] ["1024 HelloWorld true"]
+	// [LogPrefix] [WARN] [example_test.go:15] ["HelloWorld"] [key=1024]
+	// [LogPrefix] [ERROR] [example_test.go:16] ["1024 HelloWorld true"]
+}
+
+func ExampleLogger_SetLayout() {
+	logger := log.NewDeterministic()
+	logger.SetLine(false)
+	logger.SetBrackets(false)
+	logger.SetLayout("{level} {msg} {fields}")
+	logger.Info("HelloWorld", log.F("key", 1024))
+	// Output:
+	// INFO HelloWorld key=1024
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// FieldType defines the type of the value of a field.
+type FieldType uint8
+
+const (
+	// AnyType is the type of the field whose value is stored in Value.
+	AnyType FieldType = iota
+	// StringType is the type of the field whose value is stored in Str.
+	StringType
+	// IntType is the type of the field whose value is stored in Int.
+	IntType
+	// UintType is the type of the field whose value is stored in Int as uint64.
+	UintType
+	// FloatType is the type of the field whose value is stored in Int as the IEEE 754 bits.
+	FloatType
+	// BoolType is the type of the field whose value is stored in Int as 1 or 0.
+	BoolType
+	// DurationType is the type of the field whose value is stored in Int as nanoseconds.
+	DurationType
+	// TimeType is the type of the field whose value is stored in Int as Unix nanoseconds
+	// with the *time.Location in Value.
+	TimeType
+	// ErrorType is the type of the field whose error is stored in Value.
+	ErrorType
+)
+
+// Field defines a key/value pair of the record.
+//
+// The typed constructors store the value without boxing it into an interface.
+type Field struct {
+	Key   string
+	Type  FieldType
+	Int   int64
+	Str   string
+	Value interface{}
+}
+
+// F returns a new field with the key and the value.
+// A field passed to the print functions is added to the record's fields
+// instead of the message.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Any is equivalent to F.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// String returns a new field with the string value.
+func String(key string, value string) Field {
+	return Field{Key: key, Type: StringType, Str: value}
+}
+
+// Int returns a new field with the int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: IntType, Int: int64(value)}
+}
+
+// Int64 returns a new field with the int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: IntType, Int: value}
+}
+
+// Uint64 returns a new field with the uint64 value.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, Type: UintType, Int: int64(value)}
+}
+
+// Float64 returns a new field with the float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FloatType, Int: int64(math.Float64bits(value))}
+}
+
+// Bool returns a new field with the bool value.
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: BoolType, Int: i}
+}
+
+// Duration returns a new field with the duration value.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Int: int64(value)}
+}
+
+// Time returns a new field with the time value. The time out of the range of
+// the Unix nanoseconds, e.g. the zero time, is stored in Value.
+func Time(key string, value time.Time) Field {
+	if y := value.Year(); y <= 1678 || y >= 2262 {
+		return Field{Key: key, Type: TimeType, Value: value}
+	}
+	return Field{Key: key, Type: TimeType, Int: value.UnixNano(), Value: value.Location()}
+}
+
+// Err returns a new field with the key "error" and the error value.
+func Err(err error) Field {
+	return Field{Key: "error", Type: ErrorType, Value: err}
+}
+
+// Interface returns the value of the field as an interface.
+func (f Field) Interface() interface{} {
+	switch f.Type {
+	case StringType:
+		return f.Str
+	case IntType:
+		return f.Int
+	case UintType:
+		return uint64(f.Int)
+	case FloatType:
+		return math.Float64frombits(uint64(f.Int))
+	case BoolType:
+		return f.Int == 1
+	case DurationType:
+		return time.Duration(f.Int)
+	case TimeType:
+		return f.time()
+	}
+	return f.Value
+}
+
+func (f Field) time() time.Time {
+	if t, ok := f.Value.(time.Time); ok {
+		return t
+	}
+	t := time.Unix(0, f.Int)
+	if loc, ok := f.Value.(*time.Location); ok {
+		t = t.In(loc)
+	}
+	return t
+}
+
+// writeValue writes the value of the field into the buffer.
+func (e *TextEncoder) writeValue(buf *bytes.Buffer, f Field) {
+	var b [64]byte
+	switch f.Type {
+	case StringType:
+		buf.WriteString(f.Str)
+	case IntType:
+		buf.Write(strconv.AppendInt(b[:0], f.Int, 10))
+	case UintType:
+		buf.Write(strconv.AppendUint(b[:0], uint64(f.Int), 10))
+	case FloatType:
+		buf.Write(strconv.AppendFloat(b[:0], math.Float64frombits(uint64(f.Int)), 'g', -1, 64))
+	case BoolType:
+		buf.Write(strconv.AppendBool(b[:0], f.Int == 1))
+	case DurationType:
+		buf.WriteString(time.Duration(f.Int).String())
+	case TimeType:
+		buf.Write(appendTime(b[:0], f.time(), e.TimeFormat))
+	case ErrorType:
+		if err, ok := f.Value.(error); ok || f.Value == nil {
+			buf.WriteString(errorString(err))
+		} else {
+			fmt.Fprint(buf, f.Value)
+		}
+	default:
+		switch v := f.Value.(type) {
+		case string:
+			buf.WriteString(v)
+		case []byte:
+			buf.Write(v)
+		case int:
+			buf.Write(strconv.AppendInt(b[:0], int64(v), 10))
+		case int64:
+			buf.Write(strconv.AppendInt(b[:0], v, 10))
+		case int32:
+			buf.Write(strconv.AppendInt(b[:0], int64(v), 10))
+		case uint:
+			buf.Write(strconv.AppendUint(b[:0], uint64(v), 10))
+		case uint64:
+			buf.Write(strconv.AppendUint(b[:0], v, 10))
+		case uint32:
+			buf.Write(strconv.AppendUint(b[:0], uint64(v), 10))
+		case float64:
+			buf.Write(strconv.AppendFloat(b[:0], v, 'g', -1, 64))
+		case bool:
+			buf.Write(strconv.AppendBool(b[:0], v))
+		case time.Duration:
+			buf.WriteString(v.String())
+		case error:
+			buf.WriteString(errorString(v))
+		default:
+			fmt.Fprint(buf, v)
+		}
+	}
+}
+
+// errorString returns the message of the error. Like fmt, it returns "<nil>"
+// for a nil error and for a nil pointer whose Error method panics.
+func errorString(err error) (s string) {
+	if err == nil {
+		return "<nil>"
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr && v.IsNil() {
+				s = "<nil>"
+				return
+			}
+			s = "%!v(PANIC=Error method: " + fmt.Sprint(p) + ")"
+		}
+	}()
+	return err.Error()
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestFields(t *testing.T) {
+	now := time.Date(2023, 5, 13, 18, 21, 51, 183000000, time.UTC)
+	fields := []Field{
+		String("string", "HelloWorld"),
+		Int("int", -1024),
+		Int64("int64", 1024),
+		Uint64("uint64", 1<<63),
+		Float64("float64", 1.5),
+		Bool("bool", true),
+		Duration("duration", time.Second),
+		Time("time", now),
+		Err(errors.New("failed")),
+		Err(nil),
+		Any("any", []int{1, 2}),
+	}
+	values := []interface{}{"HelloWorld", int64(-1024), int64(1024), uint64(1 << 63), 1.5, true, time.Second, now, errors.New("failed"), nil, []int{1, 2}}
+	for i, f := range fields {
+		if v := f.Interface(); i != 8 && i != 10 && v != values[i] {
+			t.Errorf("error %v != %v", v, values[i])
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetTime(false)
+	l.SetLine(false)
+	l.SetStackLevel(OffLevel)
+	l.SetTimeFormat(time.RFC3339)
+	l.Log(ErrorLevel, "HelloWorld", fields...)
+	expect := `[ERROR] ["HelloWorld"] [string=HelloWorld] [int=-1024] [int64=1024] [uint64=9223372036854775808] [float64=1.5] [bool=true] [duration=1s] [time=2023-05-13T18:21:51Z] [error=failed] [error=<nil>] [any=[1 2]]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	l.Log(DebugLevel, "HelloWorld", fields...)
+	if buf.Len() > 0 {
+		t.Error(buf.String())
+	}
+}
+
+type nilError struct {
+	msg string
+}
+
+func (e *nilError) Error() string {
+	return e.msg
+}
+
+type panicError struct{}
+
+func (e panicError) Error() string {
+	panic("HelloWorld")
+}
+
+func TestFieldEdgeValues(t *testing.T) {
+	zero := time.Time{}
+	far := time.Date(3000, 1, 2, 3, 4, 5, 0, time.UTC)
+	if Time("zero", zero).Interface() != zero || Time("far", far).Interface() != far {
+		t.Error(Time("zero", zero).Interface(), Time("far", far).Interface())
+	}
+	var typedNil *nilError
+	buf := bytes.NewBuffer(nil)
+	e := &TextEncoder{TimeFormat: time.RFC3339}
+	for _, c := range []struct {
+		field  Field
+		expect string
+	}{
+		{Time("time", zero), "0001-01-01T00:00:00Z"},
+		{Time("time", far), "3000-01-02T03:04:05Z"},
+		{Err(nil), "<nil>"},
+		{Err(typedNil), "<nil>"},
+		{Any("error", typedNil), "<nil>"},
+		{Err(panicError{}), "%!v(PANIC=Error method: HelloWorld)"},
+		{Field{Type: ErrorType, Value: 1024}, "1024"},
+	} {
+		buf.Reset()
+		e.writeValue(buf, c.field)
+		if buf.String() != c.expect {
+			t.Errorf("error %s != %s", buf.String(), c.expect)
+		}
+	}
+}
+
+func TestLogAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are counted without the race detector")
+	}
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	l.SetLine(false)
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Log(InfoLevel, "HelloWorld", String("string", "HelloWorld"), Int("int", 1024), Bool("bool", true))
+	})
+	if allocs > 0 {
+		t.Errorf("error %v allocs", allocs)
+	}
+}
+
+func BenchmarkLogFields(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Log(InfoLevel, "HelloWorld", String("string", "HelloWorld"), Int("int", 1024), Bool("bool", true))
+	}
+}
+
+func BenchmarkInfoFields(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("HelloWorld", F("string", "HelloWorld"), F("int", 1024), F("bool", true))
+	}
+}
@@ -1,5 +1,8 @@
 module github.com/hslam/log
 
-go 1.15
+go 1.16
 
-require github.com/hslam/writer v1.0.1-0.20230517134517-171bf4321917
+require (
+	github.com/go-logr/logr v1.2.4
+	github.com/hslam/writer v1.0.1-0.20230517134517-171bf4321917
+)
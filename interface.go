@@ -0,0 +1,138 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Interface defines the logging methods of the Logger, so that the libraries
+// can accept alternative implementations and mocks.
+//
+// The Panic methods of a Logger panic if the panic level is enabled, and those
+// of Nop always panic. The Fatal and Assert methods of a Logger exit through
+// the function set by SetExitFunc, while those of Nop do nothing, so that Nop
+// never ends the process.
+type Interface interface {
+	// Trace is equivalent to log.Print() for trace.
+	Trace(v ...interface{})
+	// Tracef is equivalent to log.Printf() for trace.
+	Tracef(format string, v ...interface{})
+	// Traceln is equivalent to log.Println() for trace.
+	Traceln(v ...interface{})
+	// Debug is equivalent to log.Print() for debug.
+	Debug(v ...interface{})
+	// Debugf is equivalent to log.Printf() for debug.
+	Debugf(format string, v ...interface{})
+	// Debugln is equivalent to log.Println() for debug.
+	Debugln(v ...interface{})
+	// Info is equivalent to log.Print() for info.
+	Info(v ...interface{})
+	// Infof is equivalent to log.Printf() for info.
+	Infof(format string, v ...interface{})
+	// Infoln is equivalent to log.Println() for info.
+	Infoln(v ...interface{})
+	// Notice is equivalent to log.Print() for notice.
+	Notice(v ...interface{})
+	// Noticef is equivalent to log.Printf() for notice.
+	Noticef(format string, v ...interface{})
+	// Noticeln is equivalent to log.Println() for notice.
+	Noticeln(v ...interface{})
+	// Warn is equivalent to log.Print() for warn.
+	Warn(v ...interface{})
+	// Warnf is equivalent to log.Printf() for warn.
+	Warnf(format string, v ...interface{})
+	// Warnln is equivalent to log.Println() for warn.
+	Warnln(v ...interface{})
+	// Error is equivalent to log.Print() for error.
+	Error(v ...interface{})
+	// Errorf is equivalent to log.Printf() for error.
+	Errorf(format string, v ...interface{})
+	// Errorln is equivalent to log.Println() for error.
+	Errorln(v ...interface{})
+	// Panic is equivalent to log.Print() for panic.
+	Panic(v ...interface{})
+	// Panicf is equivalent to log.Printf() for panic.
+	Panicf(format string, v ...interface{})
+	// Panicln is equivalent to log.Println() for panic.
+	Panicln(v ...interface{})
+	// Fatal is equivalent to log.Print() for fatal.
+	Fatal(v ...interface{})
+	// Fatalf is equivalent to log.Printf() for fatal.
+	Fatalf(format string, v ...interface{})
+	// Fatalln is equivalent to log.Println() for fatal.
+	Fatalln(v ...interface{})
+	// Assert asserts that b is true. Otherwise, it would log fatal.
+	Assert(b bool)
+	// Assertf is equivalent to Assert with info.
+	Assertf(b bool, format string, v ...interface{})
+	// Log logs the message with the fields at the level.
+	Log(level Level, msg string, fields ...Field)
+	// Enabled reports whether the level is enabled.
+	Enabled(level Level) bool
+	// With returns a logger that adds the fields to each record.
+	With(fields ...Field) Interface
+}
+
+var _ Interface = (*Logger)(nil)
+
+// With returns a clone of the logger that adds the fields to each record.
+// It is equivalent to WithFields.
+func (l *Logger) With(fields ...Field) Interface {
+	return l.WithFields(fields...)
+}
+
+// nop implements the Interface that discards everything.
+type nop struct{}
+
+// Nop returns a logger that discards everything. Like a Logger, its Panic
+// methods panic with the message, but its Fatal and Assert methods neither
+// log nor exit.
+func Nop() Interface {
+	return nop{}
+}
+
+func (nop) Trace(v ...interface{})                          {}
+func (nop) Tracef(format string, v ...interface{})          {}
+func (nop) Traceln(v ...interface{})                        {}
+func (nop) Debug(v ...interface{})                          {}
+func (nop) Debugf(format string, v ...interface{})          {}
+func (nop) Debugln(v ...interface{})                        {}
+func (nop) Info(v ...interface{})                           {}
+func (nop) Infof(format string, v ...interface{})           {}
+func (nop) Infoln(v ...interface{})                         {}
+func (nop) Notice(v ...interface{})                         {}
+func (nop) Noticef(format string, v ...interface{})         {}
+func (nop) Noticeln(v ...interface{})                       {}
+func (nop) Warn(v ...interface{})                           {}
+func (nop) Warnf(format string, v ...interface{})           {}
+func (nop) Warnln(v ...interface{})                         {}
+func (nop) Error(v ...interface{})                          {}
+func (nop) Errorf(format string, v ...interface{})          {}
+func (nop) Errorln(v ...interface{})                        {}
+func (nop) Panic(v ...interface{})                          { panic(sprint(printMode, "", v)) }
+func (nop) Panicf(format string, v ...interface{})          { panic(sprint(printfMode, format, v)) }
+func (nop) Panicln(v ...interface{})                        { panic(sprint(printlnMode, "", v)) }
+func (nop) Fatal(v ...interface{})                          {}
+func (nop) Fatalf(format string, v ...interface{})          {}
+func (nop) Fatalln(v ...interface{})                        {}
+func (nop) Assert(b bool)                                   {}
+func (nop) Assertf(b bool, format string, v ...interface{}) {}
+func (nop) Log(level Level, msg string, fields ...Field)    {}
+func (nop) Enabled(level Level) bool                        { return false }
+func (n nop) With(fields ...Field) Interface                { return n }
+
+// sprint formats the values without the fields by the mode, which is the
+// message of the Panic functions.
+func sprint(mode int, format string, v []interface{}) string {
+	v, _ = splitFields(v)
+	switch mode {
+	case printfMode:
+		return strings.TrimSpace(fmt.Sprintf(format, v...))
+	case printlnMode:
+		return strings.TrimSpace(fmt.Sprintln(v...))
+	}
+	return strings.TrimSpace(fmt.Sprint(v...))
+}
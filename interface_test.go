@@ -0,0 +1,58 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInterface(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetOut(buf)
+	var i Interface = l
+	i.With(Int("id", 1)).Info("HelloWorld")
+	if buf.String() != `[INFO] ["HelloWorld"] [id=1]`+"\n" {
+		t.Error(buf.String())
+	}
+}
+
+func TestNop(t *testing.T) {
+	n := Nop().With(Int("id", 1))
+	if n.Enabled(FatalLevel) {
+		t.Error("")
+	}
+	n.Info("HelloWorld")
+	n.Fatal("HelloWorld")
+	n.Assert(false)
+	func() {
+		defer func() {
+			if e := recover(); e != "1024 HelloWorld" {
+				t.Errorf("%#v", e)
+			}
+		}()
+		n.Panicf("%d %s", 1024, "HelloWorld", Int("id", 1))
+	}()
+	if raceEnabled {
+		return
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		n.Info("HelloWorld")
+		n.Log(InfoLevel, "HelloWorld", Int("id", 1))
+	})
+	if allocs > 0 {
+		t.Errorf("error %v allocs", allocs)
+	}
+}
+
+func BenchmarkNop(b *testing.B) {
+	n := Nop()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.Info("HelloWorld")
+	}
+}
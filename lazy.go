@@ -0,0 +1,38 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Lazy defines a value that is evaluated only when the record is formatted,
+// e.g. log.Debug(log.Lazy(func() interface{} { return dump(v) })).
+type Lazy func() interface{}
+
+// Format formats the evaluated value with the verb and the flags.
+func (f Lazy) Format(s fmt.State, verb rune) {
+	format := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			format = append(format, byte(flag))
+		}
+	}
+	if width, ok := s.Width(); ok {
+		format = strconv.AppendInt(format, int64(width), 10)
+	}
+	if precision, ok := s.Precision(); ok {
+		format = append(format, '.')
+		format = strconv.AppendInt(format, int64(precision), 10)
+	}
+	format = append(format, string(verb)...)
+	fmt.Fprintf(s, string(format), f())
+}
+
+// MarshalJSON marshals the evaluated value.
+func (f Lazy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f())
+}
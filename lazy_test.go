@@ -0,0 +1,50 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	lazy := Lazy(func() interface{} {
+		calls++
+		return 3.14159
+	})
+	if s := fmt.Sprintf("%v|%8.2f|%-6.1f|", lazy, lazy, lazy); s != "3.14159|    3.14|3.1   |" {
+		t.Error(s)
+	}
+	if b, _ := json.Marshal(lazy); string(b) != "3.14159" {
+		t.Error(string(b))
+	}
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	calls = 0
+	l.Debug(lazy)
+	l.Debugf("%.2f", lazy)
+	if calls != 0 || buf.Len() > 0 {
+		t.Errorf("error %d %s", calls, buf.String())
+	}
+	l.Info(lazy, F("lazy", lazy))
+	if calls != 2 || !bytes.Contains(buf.Bytes(), []byte(`["3.14159"] [lazy=3.14159]`)) {
+		t.Errorf("error %d %s", calls, buf.String())
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	l := New()
+	if l.Enabled(DebugLevel) || !l.Enabled(InfoLevel) || !l.Enabled(ErrorLevel) {
+		t.Error(l.GetLevel())
+	}
+	l.SetLevel(OffLevel)
+	if l.Enabled(FatalLevel) {
+		t.Error(l.GetLevel())
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// maxLevels is the max number of the levels.
+	maxLevels = 256
+	// rankShift spaces the ranks of the built-in levels, so that the custom
+	// levels are ranked between them without changing the other ranks.
+	rankShift = 16
+)
+
+// levelInfo defines the name, the short name, the color and the rank of a level.
+type levelInfo struct {
+	name  string
+	short string
+	color []byte
+	// above is the level right below a custom level when it is registered.
+	above Level
+	// rank orders the levels. It never changes once the level is registered,
+	// and it is negative if the level is not registered.
+	rank int
+}
+
+// levelSet defines the registered levels. It is copied on write.
+type levelSet struct {
+	infos [maxLevels]levelInfo
+	// order is the levels from the lowest to the highest.
+	order []Level
+}
+
+var (
+	levelMu     sync.Mutex
+	levelsValue atomic.Value
+	// levelRanks mirrors the ranks of the registered levels for the level checks.
+	levelRanks   [maxLevels]int32
+	builtinNames = []string{"ALL", "TRACE", "DEBUG", "INFO", "NOTICE", "WARN", "ERROR", "PANIC", "FATAL", "OFF"}
+	builtinShort = []string{"A", "T", "D", "I", "N", "W", "E", "P", "F", "O"}
+)
+
+func init() {
+	s := &levelSet{}
+	for i := range s.infos {
+		s.infos[i].rank = -1
+	}
+	builtinColors := [][]byte{{}, magenta, blue, cyan, green, yellow, red, magentaBg, redBg, {}}
+	for level := AllLevel; level <= OffLevel; level++ {
+		s.infos[level] = levelInfo{name: builtinNames[level], short: builtinShort[level], color: builtinColors[level], rank: int(level) << rankShift}
+		s.order = append(s.order, level)
+	}
+	storeLevels(s)
+}
+
+// loadLevels returns the registered levels.
+func loadLevels() *levelSet {
+	return levelsValue.Load().(*levelSet)
+}
+
+// storeLevels stores the registered levels and their ranks.
+func storeLevels(s *levelSet) {
+	levelsValue.Store(s)
+	for i := range s.infos {
+		atomic.StoreInt32(&levelRanks[i], int32(s.infos[i].rank))
+	}
+}
+
+// clone returns a copy of the levels.
+func (s *levelSet) clone() *levelSet {
+	c := &levelSet{infos: s.infos}
+	c.order = make([]Level, len(s.order))
+	copy(c.order, s.order)
+	return c
+}
+
+// rank returns the rank of the level.
+func (level Level) rank() int {
+	return int(atomic.LoadInt32(&levelRanks[level]))
+}
+
+// registered reports whether the level is registered.
+func (level Level) registered() bool {
+	return level.rank() >= 0
+}
+
+// String returns the name of the level.
+func (level Level) String() string {
+	if info := loadLevels().infos[level]; info.rank >= 0 {
+		return info.name
+	}
+	return "Level(" + strconv.Itoa(int(level)) + ")"
+}
+
+// ShortString returns the short name of the level.
+func (level Level) ShortString() string {
+	if info := loadLevels().infos[level]; info.rank >= 0 {
+		return info.short
+	}
+	return level.String()
+}
+
+// color returns the highlight color of the level.
+func (level Level) color() []byte {
+	return loadLevels().infos[level].color
+}
+
+// RegisterLevel registers a custom level right above the level, e.g.
+// RegisterLevel(NoticeLevel, "AUDIT", "U", green) for a level between the
+// notice level and the warn level. The color is the ANSI escape sequence of
+// the highlight color, and nil disables the highlight. Registering the same
+// names above the same level again returns the registered level.
+func RegisterLevel(above Level, name, short string, color []byte) (Level, error) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	s := loadLevels()
+	if s.infos[above].rank < 0 {
+		return 0, errors.New("log: unknown level " + above.String())
+	}
+	if above == OffLevel {
+		return 0, errors.New("log: no level above the off level")
+	}
+	for _, level := range s.order {
+		if info := s.infos[level]; level > OffLevel && info.name == name && info.short == short && info.above == above {
+			return level, nil
+		}
+	}
+	if err := s.checkName(maxLevels, name, short); err != nil {
+		return 0, err
+	}
+	var level Level
+	for level = OffLevel + 1; s.infos[level].rank >= 0; level++ {
+		if level == maxLevels-1 {
+			return 0, errors.New("log: too many levels")
+		}
+	}
+	i := 0
+	for s.order[i] != above {
+		i++
+	}
+	low, high := s.infos[above].rank, s.infos[s.order[i+1]].rank
+	rank := low + (high-low)/2
+	if rank == low {
+		return 0, errors.New("log: too many levels above " + above.String())
+	}
+	c := s.clone()
+	c.order = append(c.order, 0)
+	copy(c.order[i+2:], c.order[i+1:])
+	c.order[i+1] = level
+	c.infos[level] = levelInfo{name: name, short: short, color: color, above: above, rank: rank}
+	storeLevels(c)
+	return level, nil
+}
+
+// SetLevelName renames the level, e.g. SetLevelName(WarnLevel, "WARNING", "W").
+func SetLevelName(level Level, name, short string) error {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	s := loadLevels()
+	if s.infos[level].rank < 0 {
+		return errors.New("log: unknown level " + level.String())
+	}
+	if err := s.checkName(int(level), name, short); err != nil {
+		return err
+	}
+	c := s.clone()
+	c.infos[level].name = name
+	c.infos[level].short = short
+	levelsValue.Store(c)
+	return nil
+}
+
+// SetLevelColor sets the highlight color of the level. The color is the ANSI
+// escape sequence, and nil disables the highlight.
+func SetLevelColor(level Level, color []byte) error {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	s := loadLevels()
+	if s.infos[level].rank < 0 {
+		return errors.New("log: unknown level " + level.String())
+	}
+	c := s.clone()
+	c.infos[level].color = color
+	levelsValue.Store(c)
+	return nil
+}
+
+// checkName returns an error if the name or the short name is empty or used
+// by another level than the level.
+func (s *levelSet) checkName(level int, name, short string) error {
+	if len(name) == 0 || len(short) == 0 {
+		return errors.New("log: empty level name")
+	}
+	for _, l := range s.order {
+		if int(l) != level && (s.infos[l].name == name || s.infos[l].short == short) {
+			return errors.New("log: duplicate level name " + strconv.Quote(name))
+		}
+	}
+	return nil
+}
+
+// levelState holds a level and its rank. It is shared by the logger and the
+// named loggers inheriting its level.
+type levelState struct {
+	rank  int32
+	level uint32
+}
+
+func newLevelState(level Level) *levelState {
+	s := &levelState{}
+	s.store(level)
+	return s
+}
+
+func (s *levelState) store(level Level) {
+	atomic.StoreUint32(&s.level, uint32(level))
+	atomic.StoreInt32(&s.rank, int32(level.rank()))
+}
+
+func (s *levelState) load() Level {
+	return Level(atomic.LoadUint32(&s.level))
+}
+
+// ParseLevel returns the level of the name or the short name.
+func ParseLevel(name string) (Level, bool) {
+	s := loadLevels()
+	for _, level := range s.order {
+		if name == s.infos[level].name || name == s.infos[level].short {
+			return level, true
+		}
+	}
+	return 0, false
+}
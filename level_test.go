@@ -0,0 +1,115 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+// restoreLevels restores the registered levels when the test finishes.
+func restoreLevels(t *testing.T) {
+	s := loadLevels()
+	t.Cleanup(func() {
+		levelMu.Lock()
+		storeLevels(s)
+		levelMu.Unlock()
+	})
+}
+
+func TestRegisterLevel(t *testing.T) {
+	restoreLevels(t)
+	audit, err := RegisterLevel(NoticeLevel, "AUDIT", "U", green)
+	if err != nil {
+		t.Fatal(err)
+	}
+	security, err := RegisterLevel(ErrorLevel, "SECURITY", "S", redBg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level, err := RegisterLevel(NoticeLevel, "AUDIT", "U", green); err != nil || level != audit {
+		t.Error(level, err)
+	}
+	if audit.String() != "AUDIT" || security.ShortString() != "S" {
+		t.Error(audit, security)
+	}
+	if WarnLevel.rank() != int(WarnLevel)<<rankShift || PanicLevel.rank() != int(PanicLevel)<<rankShift {
+		t.Error(WarnLevel.rank(), PanicLevel.rank())
+	}
+	if !(NoticeLevel.rank() < audit.rank() && audit.rank() < WarnLevel.rank()) ||
+		!(ErrorLevel.rank() < security.rank() && security.rank() < PanicLevel.rank()) {
+		t.Error(audit.rank(), security.rank())
+	}
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetOut(buf)
+	l.SetLevel(audit)
+	l.Log(NoticeLevel, "HelloWorld")
+	l.Log(audit, "HelloWorld")
+	l.Log(security, "HelloWorld")
+	if buf.String() != `[AUDIT] ["HelloWorld"]`+"\n"+`[SECURITY] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+	if level, ok := ParseLevel("U"); !ok || level != audit {
+		t.Error(level, ok)
+	}
+	if _, err := RegisterLevel(OffLevel, "OVER", "V", nil); err == nil {
+		t.Error("")
+	}
+	if _, err := RegisterLevel(InfoLevel, "AUDIT", "X", nil); err == nil {
+		t.Error("")
+	}
+	if _, err := RegisterLevel(Level(200), "UNKNOWN", "K", nil); err == nil {
+		t.Error("")
+	}
+	if Level(200).String() != "Level(200)" || l.Enabled(Level(200)) {
+		t.Error(Level(200))
+	}
+	l.SetLevel(Level(200))
+	l.SetStackLevel(Level(200))
+	l.SetFlushLevel(Level(200))
+	if l.GetLevel() != audit || l.stackLevel != OffLevel || l.flushLevel != PanicLevel {
+		t.Error(l.GetLevel(), l.stackLevel, l.flushLevel)
+	}
+}
+
+func TestRegisterLevelRanks(t *testing.T) {
+	restoreLevels(t)
+	l := New()
+	var err error
+	for i := 0; err == nil; i++ {
+		var level Level
+		level, err = RegisterLevel(NoticeLevel, "LEVEL"+strconv.Itoa(i), "L"+strconv.Itoa(i), nil)
+		if err == nil {
+			l.SetLevel(level)
+			if !l.Enabled(WarnLevel) || l.Enabled(NoticeLevel) || i > rankShift {
+				t.Fatal(level, level.rank())
+			}
+		}
+	}
+}
+
+func TestSetLevelName(t *testing.T) {
+	restoreLevels(t)
+	if err := SetLevelName(WarnLevel, "WARNING", "W"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLevelName(WarnLevel, "ERROR", "W"); err == nil {
+		t.Error("")
+	}
+	if err := SetLevelColor(WarnLevel, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetHighlight(true)
+	l.SetOut(buf)
+	l.Warn("HelloWorld")
+	if buf.String() != `[WARNING] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+}
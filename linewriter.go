@@ -0,0 +1,109 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+const (
+	// MaxLineLength is the max length of a line written to the Writer.
+	// A longer line is split into several records.
+	MaxLineLength = 64 << 10
+	// writerDepth is the depth of the caller of Write: logout, log, Write.
+	writerDepth = 3
+)
+
+var errWriterClosed = errors.New("log: write to closed writer")
+
+// lineWriter implements the io.WriteCloser interface that logs each line as a record.
+type lineWriter struct {
+	mu     sync.Mutex
+	l      *Logger
+	level  Level
+	buf    []byte
+	closed bool
+	// parse returns the level, the message and the fields of the line.
+	parse func(line string, level Level) (Level, string, []Field)
+}
+
+// Writer returns a writer that logs each line written to it as a record of the level.
+func Writer(level Level) io.WriteCloser {
+	return Default().Writer(level)
+}
+
+// Writer returns a writer that logs each line written to it as a record of the
+// level, e.g. for exec.Cmd.Stdout or http.Server.ErrorLog. The incomplete line
+// is buffered until the next write or Close, and a line longer than
+// MaxLineLength is split into several records.
+func (l *Logger) Writer(level Level) io.WriteCloser {
+	return &lineWriter{l: l, level: level}
+}
+
+// Write logs the complete lines of p and buffers the incomplete line.
+func (w *lineWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errWriterClosed
+	}
+	n = len(p)
+	if w.parse == nil && !w.l.Enabled(w.level) {
+		return n, nil
+	}
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			for len(w.buf) >= MaxLineLength {
+				w.log(w.buf[:MaxLineLength])
+				w.buf = append(w.buf[:0], w.buf[MaxLineLength:]...)
+			}
+			break
+		}
+		line := p[:i]
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, line...)
+			line = w.buf
+		}
+		for len(line) > MaxLineLength {
+			w.log(line[:MaxLineLength])
+			line = line[MaxLineLength:]
+		}
+		w.log(line)
+		w.buf = w.buf[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Close logs the buffered incomplete line. Writes after Close return an error.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+// log logs the line without the trailing carriage return.
+func (w *lineWriter) log(line []byte) {
+	level, msg := w.level, string(bytes.TrimSuffix(line, []byte{'\r'}))
+	var fields []Field
+	if w.parse != nil {
+		level, msg, fields = w.parse(msg, level)
+	}
+	if w.l.Enabled(level) {
+		w.l.logout(writerDepth, level, msg, fields, nil)
+	}
+}
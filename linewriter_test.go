@@ -0,0 +1,51 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetOut(buf)
+	w := l.Writer(WarnLevel)
+	w.Write([]byte("Hello"))
+	if buf.Len() > 0 {
+		t.Error(buf.String())
+	}
+	w.Write([]byte("World\r\nfoo\nbar"))
+	expect := `[WARN] [linewriter_test.go:21] ["HelloWorld"]` + "\n" +
+		`[WARN] [linewriter_test.go:21] ["foo"]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	buf.Reset()
+	w.Close()
+	if buf.String() != `[WARN] [linewriter_test.go:28] ["bar"]`+"\n" {
+		t.Error(buf.String())
+	}
+	if _, err := w.Write([]byte("foo\n")); err == nil {
+		t.Error("")
+	}
+	buf.Reset()
+	l.SetLine(false)
+	w = l.Writer(InfoLevel)
+	w.Write([]byte(strings.Repeat("a", MaxLineLength+1)))
+	w.Write([]byte(strings.Repeat("b", MaxLineLength*2) + "\n"))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 || len(lines[0]) != MaxLineLength+len(`[INFO] [""]`) ||
+		lines[1] != `[INFO] ["a`+strings.Repeat("b", MaxLineLength-1)+`"]` || lines[3] != `[INFO] ["b"]` {
+		t.Error(len(lines))
+	}
+	buf.Reset()
+	w = l.Writer(DebugLevel)
+	w.Write([]byte("foo\n"))
+	if buf.Len() > 0 {
+		t.Error(buf.String())
+	}
+}
@@ -7,13 +7,14 @@ package log
 import (
 	"bytes"
 	"fmt"
-	"github.com/hslam/writer"
 	"io"
 	"os"
 	"path"
+	"regexp"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -46,16 +47,23 @@ const (
 
 const (
 	ignorePackagePrefix = "github.com/hslam/log."
+	defaultSeparator    = "."
 	defaultBufferSize   = 65536
 )
 
 var (
-	logger    = New()
-	bufPool   sync.Pool
-	pcPool    sync.Pool
-	bigpcPool sync.Pool
+	defaultLogger atomic.Value
+	bufPool       sync.Pool
+	callerCache   sync.Map
+	bigpcPool     sync.Pool
+	// hookMu guards the fatal hooks of the loggers.
+	hookMu sync.Mutex
 )
 
+func init() {
+	defaultLogger.Store(New())
+}
+
 // colors
 var (
 	blackBg   = []byte{27, 91, 57, 55, 59, 52, 48, 109}
@@ -92,22 +100,6 @@ func freeBuffer(buf *bytes.Buffer) {
 	bufPool.Put(buf)
 }
 
-const pcs = 16
-
-func newPC() []uintptr {
-	if v := pcPool.Get(); v != nil {
-		pc := v.([]uintptr)
-		return pc
-	}
-	return make([]uintptr, pcs)
-}
-
-func freePC(pc []uintptr) {
-	if cap(pc) == pcs {
-		pcPool.Put(pc[:pcs])
-	}
-}
-
 const bigpcs = 4096
 
 func newBigPC() []uintptr {
@@ -124,287 +116,322 @@ func freeBigPC(pc []uintptr) {
 	}
 }
 
-// relevantCaller searches the call stack for the first function outside of log.
-// The purpose of this function is to provide more helpful error messages.
-func relevantCaller() runtime.Frame {
-	pc := newPC()
-	n := runtime.Callers(1, pc)
-	frames := runtime.CallersFrames(pc[:n])
-	var frame runtime.Frame
-	for {
-		frame, more := frames.Next()
-		if !strings.HasPrefix(frame.Function, ignorePackagePrefix) {
-			freePC(pc)
-			return frame
-		}
-		if !more {
-			break
-		}
+// callerFrame returns the frame of the function skip frames above the caller
+// of callerFrame. Only the single program counter is captured, and the frames
+// are cached by the program counter.
+func callerFrame(skip int) runtime.Frame {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) < 1 {
+		return runtime.Frame{}
 	}
-	freePC(pc)
+	if v, ok := callerCache.Load(pc[0]); ok {
+		return v.(runtime.Frame)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc[0]}).Next()
+	callerCache.Store(pc[0], frame)
 	return frame
 }
 
-const (
-	newline     = "\\n"
-	frameFormat = "%s\\n\\t%s:%d"
-)
+// stackSlack is the number of the extra frames captured for the frames of log.
+const stackSlack = 16
 
-func callStack() (s string) {
-	var stack []string
+// callStack returns the call stack outside of log. The first skip frames are
+// skipped, and the depth limits the number of the frames if it is greater than
+// zero. The frames of the packages matching the filter are skipped.
+func callStack(skip, depth int, filter []string) (stack []runtime.Frame) {
 	pc := newBigPC()
+	if depth > 0 && skip+depth+stackSlack < len(pc) {
+		pc = pc[:skip+depth+stackSlack]
+	}
 	n := runtime.Callers(1, pc)
 	frames := runtime.CallersFrames(pc[:n])
 	for {
 		frame, more := frames.Next()
-		if !strings.HasPrefix(frame.Function, ignorePackagePrefix) {
-			stack = append(stack, fmt.Sprintf(frameFormat, frame.Function, frame.File, frame.Line))
+		if !strings.HasPrefix(frame.Function, ignorePackagePrefix) &&
+			frame.Function != "runtime.goexit" && !matchPackage(frame.Function, filter) {
+			if skip > 0 {
+				skip--
+			} else if stack = append(stack, frame); depth > 0 && len(stack) >= depth {
+				break
+			}
 		}
 		if !more {
 			break
 		}
 	}
-	if len(stack) > 0 {
-		stack = stack[:len(stack)-1]
-	}
 	freeBigPC(pc)
-	return strings.Join(stack, newline)
-}
-
-// log defines the base log interface.
-type log interface {
-	// Output writes the log info to the io.Writer.
-	Output(w io.Writer, b []byte)
-}
-
-// body implements the log interface.
-type body struct {
-}
-
-// newBody returns a new log body.
-func newBody() log {
-	return body{}
-}
-
-var (
-	front = []byte("[\"")
-	back  = []byte("\"]")
-)
-
-// Output writes the log info to the io.Writer.
-func (l body) Output(w io.Writer, b []byte) {
-	w.Write(front)
-	w.Write(b)
-	w.Write(back)
+	return stack
 }
 
-// stackField implements the log interface.
-type stackField struct {
-	l log
+// funcPackage returns the package path of the function name.
+func funcPackage(function string) string {
+	i := strings.LastIndexByte(function, '/')
+	if i < 0 {
+		i = 0
+	}
+	if j := strings.IndexByte(function[i:], '.'); j >= 0 {
+		return function[:i+j]
+	}
+	return function
 }
 
-// withStackField returns a new log with the stack field.
-func withStackField(l log) log {
-	return &stackField{l}
+// matchPackage reports whether the package of the function matches any of the patterns.
+func matchPackage(function string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	pkg := funcPackage(function)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, pkg); matched {
+			return true
+		}
+	}
+	return false
 }
 
-const stackFormat = " [stack=\"%s\"]"
-
-// Output writes the log info to the io.Writer.
-func (l *stackField) Output(w io.Writer, b []byte) {
-	l.l.Output(w, b)
-	fmt.Fprintf(w, stackFormat, callStack())
+// trimPrefix trims the spaces and the brackets of the prefix.
+func trimPrefix(prefix string) string {
+	prefix = strings.TrimSpace(prefix)
+	prefix = strings.Trim(prefix, "[")
+	prefix = strings.Trim(prefix, "]")
+	prefix = strings.TrimSpace(prefix)
+	return prefix
 }
 
-// lineField implements the log interface.
-type lineField struct {
-	l log
+// Logger defines the logger.
+type Logger struct {
+	pipe           *pipeline
+	prefix         string
+	separator      string
+	level          *levelState
+	inheritLevel   bool
+	shortLevel     bool
+	highlight      bool
+	autoHighlight  bool
+	line           bool
+	encoder        Encoder
+	noTime         bool
+	utc            bool
+	timeFormat     string
+	clock          func() time.Time
+	layout         string
+	noBrackets     bool
+	stackLevel     Level
+	stackDepth     int
+	stackFilter    []string
+	multiline      bool
+	callerFormat   CallerFormat
+	callerSkip     int
+	flushLevel     Level
+	exitFunc       func(code int)
+	exitCode       int
+	fatalHooks     []func()
+	typedPanic     bool
+	sensitiveKeys  map[string]bool
+	redactPatterns []*regexp.Regexp
+	verbosity      int
+	vmodule        *vmodule
+	fields         []Field
+
+	trimmedPrefix string
+	enc           Encoder
+	colorEnc      Encoder
 }
 
-// withLineField returns a new log with the line field.
-func withLineField(l log) log {
-	return &lineField{l}
+// New creates a new Logger.
+func New() *Logger {
+	l := &Logger{
+		pipe:       newPipeline(os.Stdout, defaultBufferSize),
+		separator:  defaultSeparator,
+		level:      newLevelState(InfoLevel),
+		line:       true,
+		stackLevel: ErrorLevel,
+		flushLevel: PanicLevel,
+		exitCode:   1,
+	}
+	l.init()
+	return l
 }
 
-const callerFormat = "[%s:%d] "
-
-// Output writes the log info to the io.Writer.
-func (l *lineField) Output(w io.Writer, b []byte) {
-	caller := relevantCaller()
-	fmt.Fprintf(w, callerFormat, path.Base(caller.File), caller.Line)
-	l.l.Output(w, b)
+// NewDeterministic creates a new Logger whose output only depends on the
+// call site, for Example functions and golden files. The time field is
+// disabled, the caller is the base name of the file and the output is
+// unbuffered. The call stack is disabled too, since its frames depend on the
+// test runner and the version of Go.
+func NewDeterministic() *Logger {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetTime(false)
+	l.SetStackLevel(OffLevel)
+	return l
 }
 
-// levelField implements the log interface.
-type levelField struct {
-	l     log
-	level []byte
+// Default returns the default logger used by the package-level functions.
+func Default() *Logger {
+	return defaultLogger.Load().(*Logger)
 }
 
-// withLevelField returns a new log with the level field.
-func withLevelField(l log, level string) log {
-	return &levelField{l, []byte("[" + level + "] ")}
+// SetDefault sets the default logger used by the package-level functions.
+// A nil logger is ignored.
+func SetDefault(l *Logger) {
+	if l != nil {
+		defaultLogger.Store(l)
+	}
 }
 
-// Output writes the log info to the io.Writer.
-func (l *levelField) Output(w io.Writer, b []byte) {
-	w.Write(l.level)
-	l.l.Output(w, b)
+// ReplaceDefault sets the default logger and returns a function that restores
+// the previous default logger, e.g. defer log.ReplaceDefault(l)().
+func ReplaceDefault(l *Logger) (restore func()) {
+	prev := Default()
+	SetDefault(l)
+	return func() {
+		SetDefault(prev)
+	}
 }
 
-// timeField implements the log interface.
-type timeField struct {
-	l log
+// SetPrefix sets log's prefix
+func SetPrefix(prefix string) {
+	Default().SetPrefix(prefix)
 }
 
-// withTimeField returns a new log with the time field.
-func withTimeField(l log) log {
-	return &timeField{l}
+// SetPrefix sets log's prefix
+func (l *Logger) SetPrefix(prefix string) {
+	l.prefix = prefix
+	l.init()
 }
 
-const (
-	timeFormat    = "[2006/01/02 15:04:05.000 -07:00] "
-	timeFormatLen = len(timeFormat)
-)
-
-// Output writes the log info to the io.Writer.
-func (l *timeField) Output(w io.Writer, b []byte) {
-	var buf [timeFormatLen]byte
-	var tb = buf[:0]
-	time.Now().AppendFormat(tb, timeFormat)
-	w.Write(tb[:timeFormatLen])
-	l.l.Output(w, b)
+// GetPrefix returns log's prefix
+func GetPrefix() (prefix string) {
+	return Default().GetPrefix()
 }
 
-// prefixField implements the log interface.
-type prefixField struct {
-	l      log
-	prefix []byte
+// GetPrefix returns log's prefix
+func (l *Logger) GetPrefix() (prefix string) {
+	return l.prefix
 }
 
-// withPrefixField returns a new log with the prefix field.
-func withPrefixField(l log, prefix string) log {
-	prefix = strings.TrimSpace(prefix)
-	prefix = strings.Trim(prefix, "[")
-	prefix = strings.Trim(prefix, "]")
-	prefix = strings.TrimSpace(prefix)
-	return &prefixField{l, []byte("[" + prefix + "] ")}
+// Named returns a child logger whose prefix is the name appended to log's prefix
+// with the name separator, e.g. Named("api").Named("auth") logs with [api.auth].
+func Named(name string) *Logger {
+	return Default().Named(name)
+}
+
+// Named returns a child logger whose prefix is the name appended to the logger's
+// prefix with the name separator, e.g. Named("api").Named("auth") logs with [api.auth].
+//
+// The child shares the output, the buffer and the sinks with the logger, and
+// inherits the level of the logger until its own level is set. The loggers
+// named from an inheriting child inherit the same level as the child.
+func (l *Logger) Named(name string) *Logger {
+	c := l.clone()
+	c.inheritLevel = true
+	name = trimPrefix(name)
+	switch {
+	case len(name) == 0:
+		c.prefix = l.trimmedPrefix
+	case len(l.trimmedPrefix) == 0:
+		c.prefix = name
+	default:
+		c.prefix = l.trimmedPrefix + l.separator + name
+	}
+	c.init()
+	return c
 }
 
-// Output writes the log info to the io.Writer.
-func (l *prefixField) Output(w io.Writer, b []byte) {
-	w.Write(l.prefix)
-	l.l.Output(w, b)
+// Clone returns a copy of log's logger.
+func Clone() *Logger {
+	return Default().Clone()
 }
 
-// highlightField implements the log interface.
-type highlightField struct {
-	l     log
-	color []byte
+// Clone returns a copy of the logger that shares the output, the buffer, the
+// flush schedule and the sinks with the logger, so that the setters of the
+// copy only change the copy and the lines of both loggers never interleave.
+func (l *Logger) Clone() *Logger {
+	c := l.clone()
+	c.level = newLevelState(l.GetLevel())
+	c.inheritLevel = false
+	c.init()
+	return c
 }
 
-// withHighlightField returns a new log with the highlight field.
-func withHighlightField(l log, color []byte) log {
-	return &highlightField{l, color}
+func (l *Logger) clone() *Logger {
+	hookMu.Lock()
+	c := *l
+	hookMu.Unlock()
+	return &c
 }
 
-// Output writes the log info to the io.Writer.
-func (l *highlightField) Output(w io.Writer, b []byte) {
-	w.Write(l.color)
-	l.l.Output(w, b)
-	w.Write(reset)
+// WithPrefix returns a clone of log's logger with the prefix.
+func WithPrefix(prefix string) *Logger {
+	return Default().WithPrefix(prefix)
 }
 
-var levels = [9]string{"ALL", "TRACE", "DEBUG", "INFO", "NOTICE", "WARN", "ERROR", "PANIC", "FATAL"}
-var shortLevels = [9]string{"A", "T", "D", "I", "N", "W", "E", "P", "F"}
-
-var colors = [9][]byte{{}, magenta, blue, cyan, green, yellow, red, magentaBg, redBg}
-
-func newLog(prefix string, level Level, shortLevel, highlight, line bool) log {
-	l := newBody()
-	if line {
-		l = withLineField(l)
-	}
-	if level >= ErrorLevel {
-		l = withStackField(l)
-	}
-	if shortLevel {
-		l = withLevelField(l, shortLevels[level])
-	} else {
-		l = withLevelField(l, levels[level])
-	}
-	l = withTimeField(l)
-	if len(prefix) > 0 {
-		l = withPrefixField(l, prefix)
-	}
-	color := colors[level]
-	if len(color) > 0 && highlight {
-		l = withHighlightField(l, color)
-	}
-	return l
+// WithPrefix returns a clone of the logger with the prefix.
+func (l *Logger) WithPrefix(prefix string) *Logger {
+	c := l.Clone()
+	c.SetPrefix(prefix)
+	return c
 }
 
-// Logger defines the logger.
-type Logger struct {
-	mu         sync.Mutex
-	out        io.Writer
-	writer     io.Writer
-	bufferSize int
-	prefix     string
-	level      Level
-	shortLevel bool
-	highlight  bool
-	line       bool
-	logs       [9]log
+// WithLevel returns a clone of log's logger with the level.
+func WithLevel(level Level) *Logger {
+	return Default().WithLevel(level)
 }
 
-// New creates a new Logger.
-func New() *Logger {
-	l := &Logger{
-		out:        os.Stdout,
-		level:      InfoLevel,
-		line:       true,
-		bufferSize: defaultBufferSize,
-	}
-	l.init()
-	return l
+// WithLevel returns a clone of the logger with the level.
+func (l *Logger) WithLevel(level Level) *Logger {
+	c := l.Clone()
+	c.SetLevel(level)
+	return c
 }
 
-// SetPrefix sets log's prefix
-func SetPrefix(prefix string) {
-	logger.SetPrefix(prefix)
+// WithFields returns a clone of log's logger that adds the fields to each record.
+func WithFields(fields ...Field) *Logger {
+	return Default().WithFields(fields...)
 }
 
-// SetPrefix sets log's prefix
-func (l *Logger) SetPrefix(prefix string) {
-	l.prefix = prefix
-	l.init()
+// WithFields returns a clone of the logger that adds the fields to each record
+// before the fields of the call.
+func (l *Logger) WithFields(fields ...Field) *Logger {
+	c := l.Clone()
+	c.fields = make([]Field, 0, len(l.fields)+len(fields))
+	c.fields = append(c.fields, l.fields...)
+	c.fields = append(c.fields, fields...)
+	return c
 }
 
-// GetPrefix returns log's prefix
-func GetPrefix() (prefix string) {
-	return logger.GetPrefix()
+// SetNameSeparator sets the separator between the names of the named loggers. The default is ".".
+func SetNameSeparator(separator string) {
+	Default().SetNameSeparator(separator)
 }
 
-// GetPrefix returns log's prefix
-func (l *Logger) GetPrefix() (prefix string) {
-	return l.prefix
+// SetNameSeparator sets the separator between the names of the named loggers. The default is ".".
+func (l *Logger) SetNameSeparator(separator string) {
+	l.separator = separator
 }
 
-// SetLevel sets log's level
+// SetLevel sets log's level. An unregistered level is ignored.
 func SetLevel(level Level) {
-	logger.SetLevel(level)
+	Default().SetLevel(level)
 }
 
-// SetLevel sets log's level
+// SetLevel sets log's level. An unregistered level is ignored.
 func (l *Logger) SetLevel(level Level) {
-	l.level = level
+	if !level.registered() {
+		return
+	}
+	if l.inheritLevel {
+		l.level = newLevelState(level)
+		l.inheritLevel = false
+	} else {
+		l.level.store(level)
+	}
 	l.init()
 }
 
 // SetShortLevel sets whether to enable the short level name.
 func SetShortLevel(shortLevel bool) {
-	logger.SetShortLevel(shortLevel)
+	Default().SetShortLevel(shortLevel)
 }
 
 // SetShortLevel sets whether to enable the short level name.
@@ -415,7 +442,7 @@ func (l *Logger) SetShortLevel(shortLevel bool) {
 
 // SetHighlight sets whether to enable the highlight field.
 func SetHighlight(highlight bool) {
-	logger.SetHighlight(highlight)
+	Default().SetHighlight(highlight)
 }
 
 // SetHighlight sets whether to enable the highlight field.
@@ -426,7 +453,7 @@ func (l *Logger) SetHighlight(highlight bool) {
 
 // SetLine sets whether to enable the line field .
 func SetLine(line bool) {
-	logger.SetLine(line)
+	Default().SetLine(line)
 }
 
 // SetLine sets whether to enable the line field .
@@ -435,298 +462,754 @@ func (l *Logger) SetLine(line bool) {
 	l.init()
 }
 
+// SetTime sets whether to enable the time field.
+func SetTime(enable bool) {
+	Default().SetTime(enable)
+}
+
+// SetTime sets whether to enable the time field.
+func (l *Logger) SetTime(enable bool) {
+	l.noTime = !enable
+}
+
+// SetTimeFormat sets the layout of the time field. An empty format
+// restores DefaultTimeFormat.
+func SetTimeFormat(format string) {
+	Default().SetTimeFormat(format)
+}
+
+// SetTimeFormat sets the layout of the time field. An empty format
+// restores DefaultTimeFormat.
+func (l *Logger) SetTimeFormat(format string) {
+	l.timeFormat = format
+	l.init()
+}
+
+// SetUTC sets whether to use UTC instead of the local time zone.
+func SetUTC(utc bool) {
+	Default().SetUTC(utc)
+}
+
+// SetUTC sets whether to use UTC instead of the local time zone.
+func (l *Logger) SetUTC(utc bool) {
+	l.utc = utc
+}
+
+// SetClock sets the function that returns the current time.
+// A nil clock restores time.Now.
+func SetClock(clock func() time.Time) {
+	Default().SetClock(clock)
+}
+
+// SetClock sets the function that returns the current time.
+// A nil clock restores time.Now.
+func (l *Logger) SetClock(clock func() time.Time) {
+	l.clock = clock
+}
+
+// SetLayout sets the template of the text line, e.g.
+// "{time} {level} {prefix} {caller} {msg} {fields}". An empty layout
+// restores DefaultLayout.
+func SetLayout(layout string) {
+	Default().SetLayout(layout)
+}
+
+// SetLayout sets the template of the text line, e.g.
+// "{time} {level} {prefix} {caller} {msg} {fields}". An empty layout
+// restores DefaultLayout.
+func (l *Logger) SetLayout(layout string) {
+	l.layout = layout
+	l.init()
+}
+
+// SetBrackets sets whether to enable the brackets around the fields.
+func SetBrackets(brackets bool) {
+	Default().SetBrackets(brackets)
+}
+
+// SetBrackets sets whether to enable the brackets around the fields.
+func (l *Logger) SetBrackets(brackets bool) {
+	l.noBrackets = !brackets
+	l.init()
+}
+
+// SetCallerFormat sets the format of the caller field.
+func SetCallerFormat(format CallerFormat) {
+	Default().SetCallerFormat(format)
+}
+
+// SetCallerFormat sets the format of the caller field.
+func (l *Logger) SetCallerFormat(format CallerFormat) {
+	l.callerFormat = format
+	l.init()
+}
+
+// SetCallerSkip sets the number of the frames to skip after the first
+// frame outside of log, so that the helper functions wrapping the logger
+// are reported neither as the caller nor in the call stack.
+func SetCallerSkip(skip int) {
+	Default().SetCallerSkip(skip)
+}
+
+// SetCallerSkip sets the number of the frames to skip after the first
+// frame outside of log, so that the helper functions wrapping the logger
+// are reported neither as the caller nor in the call stack.
+func (l *Logger) SetCallerSkip(skip int) {
+	l.callerSkip = skip
+}
+
+// AddCallerSkip returns a clone of log's logger that skips n more frames
+// when reporting the caller.
+func AddCallerSkip(n int) *Logger {
+	return Default().AddCallerSkip(n)
+}
+
+// AddCallerSkip returns a clone of the logger that skips n more frames when
+// reporting the caller, so that each layer of wrappers can add its own depth.
+func (l *Logger) AddCallerSkip(n int) *Logger {
+	c := l.Clone()
+	c.callerSkip += n
+	return c
+}
+
+// GetCallerSkip returns the number of the frames to skip after the first frame outside of log.
+func GetCallerSkip() int {
+	return Default().GetCallerSkip()
+}
+
+// GetCallerSkip returns the number of the frames to skip after the first frame outside of log.
+func (l *Logger) GetCallerSkip() int {
+	return l.callerSkip
+}
+
+// SetStackLevel sets the lowest level that captures the call stack.
+// The OffLevel disables the call stack. An unregistered level is ignored.
+func SetStackLevel(level Level) {
+	Default().SetStackLevel(level)
+}
+
+// SetStackLevel sets the lowest level that captures the call stack.
+// The OffLevel disables the call stack. An unregistered level is ignored.
+func (l *Logger) SetStackLevel(level Level) {
+	if level.registered() {
+		l.stackLevel = level
+	}
+}
+
+// SetStackDepth sets the maximum number of the frames of the call stack.
+// Zero means no limit.
+func SetStackDepth(depth int) {
+	Default().SetStackDepth(depth)
+}
+
+// SetStackDepth sets the maximum number of the frames of the call stack.
+// Zero means no limit.
+func (l *Logger) SetStackDepth(depth int) {
+	l.stackDepth = depth
+}
+
+// SetStackFilter sets the patterns of the packages whose frames are skipped
+// in the call stack. The patterns use the syntax of path.Match, e.g. "runtime"
+// or "net/*".
+func SetStackFilter(patterns ...string) {
+	Default().SetStackFilter(patterns...)
+}
+
+// SetStackFilter sets the patterns of the packages whose frames are skipped
+// in the call stack. The patterns use the syntax of path.Match, e.g. "runtime"
+// or "net/*".
+func (l *Logger) SetStackFilter(patterns ...string) {
+	l.stackFilter = patterns
+}
+
+// SetMultilineStack sets whether to write the call stack in multiple lines
+// like a goroutine trace instead of a field of the line.
+func SetMultilineStack(multiline bool) {
+	Default().SetMultilineStack(multiline)
+}
+
+// SetMultilineStack sets whether to write the call stack in multiple lines
+// like a goroutine trace instead of a field of the line.
+func (l *Logger) SetMultilineStack(multiline bool) {
+	l.multiline = multiline
+	l.init()
+}
+
 // SetOut sets log's writer. The out variable sets the
 // destination to which log data will be written.
 func SetOut(w io.Writer) {
-	logger.SetOut(w)
+	Default().SetOut(w)
 }
 
 // SetOut sets log's writer. The out variable sets the
 // destination to which log data will be written.
+//
+// The output is shared with the loggers derived from the logger.
 func (l *Logger) SetOut(w io.Writer) {
-	l.out = w
-	l.init()
+	l.pipe.setOut(w)
 }
 
 // SetBufferedOutput sets the buffered writer with the buffer size.
 func SetBufferedOutput(bufferSize int) {
-	logger.SetBufferedOutput(bufferSize)
+	Default().SetBufferedOutput(bufferSize)
 }
 
 // SetBufferedOutput sets the buffered writer with the buffer size.
+//
+// The output is shared with the loggers derived from the logger.
 func (l *Logger) SetBufferedOutput(bufferSize int) {
-	l.bufferSize = bufferSize
+	l.pipe.setBufferSize(bufferSize)
+}
+
+// SetEncoder sets the encoder of the records. A nil encoder
+// restores the default text encoder.
+func SetEncoder(encoder Encoder) {
+	Default().SetEncoder(encoder)
+}
+
+// SetEncoder sets the encoder of the records. A nil encoder
+// restores the default text encoder.
+func (l *Logger) SetEncoder(encoder Encoder) {
+	l.encoder = encoder
 	l.init()
 }
 
 // GetLevel returns log's level
 func GetLevel() Level {
-	return logger.GetLevel()
+	return Default().GetLevel()
 }
 
 // GetLevel returns log's level
+//
+// A named logger inherits the level of its parent until its level is set.
 func (l *Logger) GetLevel() Level {
-	return l.level
+	return l.level.load()
+}
+
+// Enabled reports whether the level is enabled.
+func Enabled(level Level) bool {
+	return Default().Enabled(level)
+}
+
+// Enabled reports whether the level is enabled.
+func (l *Logger) Enabled(level Level) bool {
+	return atomic.LoadInt32(&l.level.rank) <= atomic.LoadInt32(&levelRanks[level])
 }
 
 // Flush writes any buffered data to the underlying io.Writer.
 func Flush() {
-	logger.Flush()
+	Default().Flush()
 }
 
 // Flush writes any buffered data to the underlying io.Writer.
 func (l *Logger) Flush() {
-	l.write(true, nil)
+	l.pipe.write(true, nil)
+}
+
+// AddSink adds a sink that receives the records in addition to the output.
+func AddSink(sink Sink) {
+	Default().AddSink(sink)
+}
+
+// AddSink adds a sink that receives the records in addition to the output.
+func (l *Logger) AddSink(sink Sink) {
+	l.pipe.addSink(sink)
+}
+
+// SetExitFunc sets the function called by the Fatal and Assert functions
+// to exit. A nil function restores os.Exit.
+func SetExitFunc(exit func(code int)) {
+	Default().SetExitFunc(exit)
+}
+
+// SetExitFunc sets the function called by the Fatal and Assert functions
+// to exit. A nil function restores os.Exit.
+func (l *Logger) SetExitFunc(exit func(code int)) {
+	l.exitFunc = exit
+}
+
+// SetExitCode sets the exit code of the Fatal and Assert functions. The default is 1.
+func SetExitCode(code int) {
+	Default().SetExitCode(code)
+}
+
+// SetExitCode sets the exit code of the Fatal and Assert functions. The default is 1.
+func (l *Logger) SetExitCode(code int) {
+	l.exitCode = code
+}
+
+// AddFatalHook adds a hook run by the Fatal and Assert functions before exiting.
+func AddFatalHook(hook func()) {
+	Default().AddFatalHook(hook)
+}
+
+// AddFatalHook adds a hook run by the Fatal and Assert functions before exiting.
+// The hooks added to a clone are not run by the logger.
+func (l *Logger) AddFatalHook(hook func()) {
+	hookMu.Lock()
+	hooks := l.fatalHooks
+	l.fatalHooks = append(hooks[:len(hooks):len(hooks)], hook)
+	hookMu.Unlock()
+}
+
+// SetTypedPanic sets whether the Panic functions panic with a *PanicError
+// carrying the record and the original arguments instead of a string.
+func SetTypedPanic(typed bool) {
+	Default().SetTypedPanic(typed)
+}
+
+// SetTypedPanic sets whether the Panic functions panic with a *PanicError
+// carrying the record and the original arguments instead of a string.
+func (l *Logger) SetTypedPanic(typed bool) {
+	l.typedPanic = typed
+}
+
+// SetFlushLevel sets the lowest level that flushes the buffered output
+// after the record is written. An unregistered level is ignored.
+func SetFlushLevel(level Level) {
+	Default().SetFlushLevel(level)
+}
+
+// SetFlushLevel sets the lowest level that flushes the buffered output
+// after the record is written. An unregistered level is ignored.
+func (l *Logger) SetFlushLevel(level Level) {
+	if level.registered() {
+		l.flushLevel = level
+	}
+}
+
+// SetFlushInterval sets the interval to flush the buffered output periodically
+// in the background. Zero stops the periodic flush.
+func SetFlushInterval(d time.Duration) {
+	Default().SetFlushInterval(d)
+}
+
+// SetFlushInterval sets the interval to flush the buffered output periodically
+// in the background. Zero stops the periodic flush.
+func (l *Logger) SetFlushInterval(d time.Duration) {
+	l.pipe.setFlushInterval(d)
+}
+
+// SetFlushOnSignal sets whether to flush the buffered output when the process
+// receives SIGTERM. After flushing, the signal is raised again so that the
+// default behavior of the process is preserved. Applications handling SIGTERM
+// themselves should call Flush instead.
+func SetFlushOnSignal(enable bool) {
+	Default().SetFlushOnSignal(enable)
+}
+
+// SetFlushOnSignal sets whether to flush the buffered output when the process
+// receives SIGTERM. After flushing, the signal is raised again so that the
+// default behavior of the process is preserved. Applications handling SIGTERM
+// themselves should call Flush instead.
+func (l *Logger) SetFlushOnSignal(enable bool) {
+	l.pipe.setFlushOnSignal(enable)
 }
 
 func (l *Logger) init() {
-	if w, ok := l.writer.(*writer.Writer); ok {
-		w.Close()
+	l.trimmedPrefix = trimPrefix(l.prefix)
+	if l.encoder != nil {
+		l.enc = l.encoder
+		l.colorEnc = l.encoder
+	} else {
+		l.enc = l.textEncoder(l.highlight)
+		l.colorEnc = l.textEncoder(true)
 	}
-	if l.bufferSize > 0 {
-		l.writer = writer.NewWriter(l.out, l.bufferSize)
+}
+
+// textEncoder returns a text encoder with the settings of the logger.
+func (l *Logger) textEncoder(highlight bool) *TextEncoder {
+	return &TextEncoder{
+		ShortLevel:   l.shortLevel,
+		Highlight:    highlight,
+		TimeFormat:   l.timeFormat,
+		Layout:       l.layout,
+		NoBrackets:   l.noBrackets,
+		Multiline:    l.multiline,
+		CallerFormat: l.callerFormat,
+	}
+}
+
+func (l *Logger) now() time.Time {
+	var t time.Time
+	if l.clock != nil {
+		t = l.clock()
 	} else {
-		l.writer = l.out
+		t = time.Now()
+	}
+	if l.utc {
+		t = t.UTC()
+	}
+	return t
+}
+
+// the depths of the callers of the logger from logout
+const (
+	// logout, output, print/printf/println/panic, the level function
+	printDepth = 4
+	// logout, Log
+	logDepth = 2
+)
+
+// logout writes the record. The record is copied to keep if keep is not nil.
+// The depth is the number of the frames between logout and the caller of the logger.
+func (l *Logger) logout(depth int, level Level, msg string, fields []Field, keep *Record) {
+	r := newRecord()
+	if !l.noTime {
+		r.Time = l.now()
+	}
+	r.Level = level
+	r.Prefix = l.trimmedPrefix
+	if l.line {
+		r.Caller = callerFrame(depth + l.callerSkip)
+	}
+	r.Message = msg
+	if level.rank() >= l.stackLevel.rank() {
+		r.Stack = callStack(l.callerSkip, l.stackDepth, l.stackFilter)
 	}
-	for i := 0; i < 9; i++ {
-		l.logs[i] = newLog(l.prefix, Level(i), l.shortLevel, l.highlight, l.line)
+	if len(l.fields)+len(fields) > 0 {
+		r.fields = append(append(r.fields[:0], l.fields...), fields...)
+		r.Fields = r.fields
 	}
+	l.emit(r)
+	if keep != nil {
+		*keep = *r
+		keep.fields = nil
+		if r.Fields != nil {
+			keep.Fields = append([]Field(nil), r.Fields...)
+		}
+	}
+	freeRecord(r)
 }
 
-func (l *Logger) logout(level Level, body []byte) {
+// emit encodes the record and writes it.
+func (l *Logger) emit(r *Record) {
+	l.redact(r)
+	for _, sink := range l.pipe.loadSinks() {
+		sink.Log(r)
+	}
+	enc := l.enc
+	if l.autoHighlight && l.pipe.colored() {
+		enc = l.colorEnc
+	}
 	buf := newBuffer()
-	l.logs[level].Output(buf, bytes.TrimSpace(body))
-	fmt.Fprintln(buf)
-	l.write(level >= PanicLevel, buf.Bytes())
+	enc.Encode(buf, r)
+	l.pipe.write(r.Level.rank() >= l.flushLevel.rank(), buf.Bytes())
 	freeBuffer(buf)
 }
 
-func (l *Logger) write(flush bool, b []byte) {
-	l.mu.Lock()
-	if len(b) > 0 {
-		l.writer.Write(b)
+// splitFields splits the fields from the values.
+func splitFields(v []interface{}) ([]interface{}, []Field) {
+	n := 0
+	for _, e := range v {
+		if _, ok := e.(Field); ok {
+			n++
+		}
 	}
-	if flush {
-		if w, ok := l.writer.(*writer.Writer); ok {
-			w.Flush()
+	if n == 0 {
+		return v, nil
+	}
+	values := make([]interface{}, 0, len(v)-n)
+	fields := make([]Field, 0, n)
+	for _, e := range v {
+		if f, ok := e.(Field); ok {
+			fields = append(fields, f)
+		} else {
+			values = append(values, e)
 		}
 	}
-	l.mu.Unlock()
+	return values, fields
 }
 
-func (l *Logger) print(level Level, v ...interface{}) {
+// modes of the output
+const (
+	printMode = iota
+	printfMode
+	printlnMode
+)
+
+// output formats the values by the mode and writes the record.
+// The record is copied to keep if keep is not nil.
+func (l *Logger) output(level Level, keep *Record, mode int, format string, v []interface{}) {
+	v, fields := splitFields(v)
+	if mode != printfMode && len(v) == 1 {
+		if msg, ok := v[0].(string); ok {
+			l.logout(printDepth, level, strings.TrimSpace(msg), fields, keep)
+			return
+		}
+	}
 	body := newBuffer()
-	fmt.Fprint(body, v...)
-	l.logout(level, body.Bytes())
+	switch mode {
+	case printfMode:
+		fmt.Fprintf(body, format, v...)
+	case printlnMode:
+		fmt.Fprintln(body, v...)
+	default:
+		fmt.Fprint(body, v...)
+	}
+	l.logout(printDepth, level, string(bytes.TrimSpace(body.Bytes())), fields, keep)
 	freeBuffer(body)
 }
 
+// The print functions are not inlined, so that the level functions calling
+// them stay cheap enough to be inlined at the call sites.
+
+//go:noinline
+func (l *Logger) print(level Level, v ...interface{}) {
+	l.output(level, nil, printMode, "", v)
+}
+
+//go:noinline
 func (l *Logger) printf(level Level, format string, v ...interface{}) {
-	body := newBuffer()
-	fmt.Fprintf(body, format, v...)
-	l.logout(level, body.Bytes())
-	freeBuffer(body)
+	l.output(level, nil, printfMode, format, v)
 }
 
+//go:noinline
 func (l *Logger) println(level Level, v ...interface{}) {
-	body := newBuffer()
-	fmt.Fprintln(body, v...)
-	l.logout(level, body.Bytes())
-	freeBuffer(body)
+	l.output(level, nil, printlnMode, "", v)
+}
+
+// Log logs the message with the fields at the level. The typed fields
+// are encoded without boxing the values. It neither panics nor exits.
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	if l.Enabled(level) {
+		l.logout(logDepth, level, msg, fields, nil)
+	}
+}
+
+// PanicError is the value of the panic raised by the Panic functions
+// when the typed panic is enabled.
+type PanicError struct {
+	// Record is the logged record.
+	Record Record
+	// Format is the format of Panicf.
+	Format string
+	// Args are the original arguments.
+	Args []interface{}
+}
+
+// Error returns the message of the record.
+func (e *PanicError) Error() string {
+	return e.Record.Message
+}
+
+// panic logs the values at the panic level and panics.
+// The value of the panic is the message without the fields unless the typed
+// panic is enabled.
+func (l *Logger) panic(mode int, format string, v []interface{}) {
+	e := &PanicError{Format: format, Args: v}
+	l.output(PanicLevel, &e.Record, mode, format, v)
+	if !l.typedPanic {
+		panic(e.Record.Message)
+	}
+	panic(e)
+}
+
+// exit runs the fatal hooks, flushes the buffered output and exits.
+func (l *Logger) exit() {
+	hookMu.Lock()
+	hooks := l.fatalHooks
+	hookMu.Unlock()
+	for _, hook := range hooks {
+		hook()
+	}
+	l.Flush()
+	if l.exitFunc != nil {
+		l.exitFunc(l.exitCode)
+	} else {
+		os.Exit(l.exitCode)
+	}
 }
 
 // All is equivalent to log.Print() for all log.
 func (l *Logger) All(v ...interface{}) {
-	if l.level <= AllLevel {
+	if l.Enabled(AllLevel) {
 		l.print(AllLevel, v...)
 	}
 }
 
 // Allf is equivalent to log.Printf() for all log.
 func (l *Logger) Allf(format string, v ...interface{}) {
-	if l.level <= AllLevel {
+	if l.Enabled(AllLevel) {
 		l.printf(AllLevel, format, v...)
 	}
 }
 
 // Allln is equivalent to log.Println() for all log.
 func (l *Logger) Allln(v ...interface{}) {
-	if l.level <= AllLevel {
+	if l.Enabled(AllLevel) {
 		l.println(AllLevel, v...)
 	}
 }
 
 // Trace is equivalent to log.Print() for trace.
 func (l *Logger) Trace(v ...interface{}) {
-	if l.level <= TraceLevel {
+	if l.Enabled(TraceLevel) {
 		l.print(TraceLevel, v...)
 	}
 }
 
 // Tracef is equivalent to log.Printf() for trace.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if l.level <= TraceLevel {
+	if l.Enabled(TraceLevel) {
 		l.printf(TraceLevel, format, v...)
 	}
 }
 
 // Traceln is equivalent to log.Println() for trace.
 func (l *Logger) Traceln(v ...interface{}) {
-	if l.level <= TraceLevel {
+	if l.Enabled(TraceLevel) {
 		l.println(TraceLevel, v...)
 	}
 }
 
 // Debug is equivalent to log.Print() for debug.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.level <= DebugLevel {
+	if l.Enabled(DebugLevel) {
 		l.print(DebugLevel, v...)
 	}
 }
 
 // Debugf is equivalent to log.Printf() for debug.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.level <= DebugLevel {
+	if l.Enabled(DebugLevel) {
 		l.printf(DebugLevel, format, v...)
 	}
 }
 
 // Debugln is equivalent to log.Println() for debug.
 func (l *Logger) Debugln(v ...interface{}) {
-	if l.level <= DebugLevel {
+	if l.Enabled(DebugLevel) {
 		l.println(DebugLevel, v...)
 	}
 }
 
 // Info is equivalent to log.Print() for info.
 func (l *Logger) Info(v ...interface{}) {
-	if l.level <= InfoLevel {
+	if l.Enabled(InfoLevel) {
 		l.print(InfoLevel, v...)
 	}
 }
 
 // Infof is equivalent to log.Printf() for info.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.level <= InfoLevel {
+	if l.Enabled(InfoLevel) {
 		l.printf(InfoLevel, format, v...)
 	}
 }
 
 // Infoln is equivalent to log.Println() for info.
 func (l *Logger) Infoln(v ...interface{}) {
-	if l.level <= InfoLevel {
+	if l.Enabled(InfoLevel) {
 		l.println(InfoLevel, v...)
 	}
 }
 
 // Notice is equivalent to log.Print() for notice.
 func (l *Logger) Notice(v ...interface{}) {
-	if l.level <= NoticeLevel {
+	if l.Enabled(NoticeLevel) {
 		l.print(NoticeLevel, v...)
 	}
 }
 
 // Noticef is equivalent to log.Printf() for notice.
 func (l *Logger) Noticef(format string, v ...interface{}) {
-	if l.level <= NoticeLevel {
+	if l.Enabled(NoticeLevel) {
 		l.printf(NoticeLevel, format, v...)
 	}
 }
 
 // Noticeln is equivalent to log.Println() for notice.
 func (l *Logger) Noticeln(v ...interface{}) {
-	if l.level <= NoticeLevel {
+	if l.Enabled(NoticeLevel) {
 		l.println(NoticeLevel, v...)
 	}
 }
 
 // Warn is equivalent to log.Print() for warn.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.level <= WarnLevel {
+	if l.Enabled(WarnLevel) {
 		l.print(WarnLevel, v...)
 	}
 }
 
 // Warnf is equivalent to log.Printf() for warn.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.level <= InfoLevel {
+	if l.Enabled(WarnLevel) {
 		l.printf(WarnLevel, format, v...)
 	}
 }
 
 // Warnln is equivalent to log.Println() for warn.
 func (l *Logger) Warnln(v ...interface{}) {
-	if l.level <= WarnLevel {
+	if l.Enabled(WarnLevel) {
 		l.println(WarnLevel, v...)
 	}
 }
 
 // Error is equivalent to log.Print() for error.
 func (l *Logger) Error(v ...interface{}) {
-	if l.level <= ErrorLevel {
+	if l.Enabled(ErrorLevel) {
 		l.print(ErrorLevel, v...)
 	}
 }
 
 // Errorf is equivalent to log.Printf() for error.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.level <= ErrorLevel {
+	if l.Enabled(ErrorLevel) {
 		l.printf(ErrorLevel, format, v...)
 	}
 }
 
 // Errorln is equivalent to log.Println() for error.
 func (l *Logger) Errorln(v ...interface{}) {
-	if l.level <= ErrorLevel {
+	if l.Enabled(ErrorLevel) {
 		l.println(ErrorLevel, v...)
 	}
 }
 
 // Panic is equivalent to log.Print() for panic.
 func (l *Logger) Panic(v ...interface{}) {
-	if l.level <= PanicLevel {
-		l.print(PanicLevel, v...)
-		panic(fmt.Sprint(v...))
+	if l.Enabled(PanicLevel) {
+		l.panic(printMode, "", v)
 	}
 }
 
 // Panicf is equivalent to log.Printf() for panic.
 func (l *Logger) Panicf(format string, v ...interface{}) {
-	if l.level <= PanicLevel {
-		l.printf(PanicLevel, format, v...)
-		panic(fmt.Sprintf(format, v...))
+	if l.Enabled(PanicLevel) {
+		l.panic(printfMode, format, v)
 	}
 }
 
 // Panicln is equivalent to log.Println() for panic.
 func (l *Logger) Panicln(v ...interface{}) {
-	if l.level <= PanicLevel {
-		l.println(PanicLevel, v...)
-		panic(fmt.Sprintln(v...))
+	if l.Enabled(PanicLevel) {
+		l.panic(printlnMode, "", v)
 	}
 }
 
 // Fatal is equivalent to log.Print() for fatal.
 func (l *Logger) Fatal(v ...interface{}) {
-	if l.level <= FatalLevel {
+	if l.Enabled(FatalLevel) {
 		l.print(FatalLevel, v...)
-		os.Exit(1)
+		l.exit()
 	}
 }
 
 // Fatalf is equivalent to log.Printf() for fatal.
 func (l *Logger) Fatalf(format string, v ...interface{}) {
-	if l.level <= FatalLevel {
+	if l.Enabled(FatalLevel) {
 		l.printf(FatalLevel, format, v...)
-		os.Exit(1)
+		l.exit()
 	}
 }
 
 // Fatalln is equivalent to log.Println() for fatal.
 func (l *Logger) Fatalln(v ...interface{}) {
-	if l.level <= FatalLevel {
+	if l.Enabled(FatalLevel) {
 		l.println(FatalLevel, v...)
-		os.Exit(1)
+		l.exit()
 	}
 }
 
@@ -734,7 +1217,7 @@ func (l *Logger) Fatalln(v ...interface{}) {
 func (l *Logger) Assert(b bool) {
 	if !b {
 		l.print(FatalLevel, "Assert failed")
-		os.Exit(1)
+		l.exit()
 	}
 }
 
@@ -742,151 +1225,224 @@ func (l *Logger) Assert(b bool) {
 func (l *Logger) Assertf(b bool, format string, v ...interface{}) {
 	if !b {
 		l.printf(FatalLevel, format, v...)
-		os.Exit(1)
+		l.exit()
+	}
+}
+
+// Log logs the message with the fields at the level. The typed fields
+// are encoded without boxing the values. It neither panics nor exits.
+func Log(level Level, msg string, fields ...Field) {
+	if l := Default(); l.Enabled(level) {
+		l.logout(logDepth, level, msg, fields, nil)
 	}
 }
 
 // All is equivalent to log.Print() for all log.
 func All(v ...interface{}) {
-	logger.All(v...)
+	if l := Default(); l.Enabled(AllLevel) {
+		l.print(AllLevel, v...)
+	}
 }
 
 // Allf is equivalent to log.Printf() for all log.
 func Allf(format string, v ...interface{}) {
-	logger.Allf(format, v...)
+	if l := Default(); l.Enabled(AllLevel) {
+		l.printf(AllLevel, format, v...)
+	}
 }
 
 // Allln is equivalent to log.Println() for all log.
 func Allln(v ...interface{}) {
-	logger.Allln(v...)
+	if l := Default(); l.Enabled(AllLevel) {
+		l.println(AllLevel, v...)
+	}
 }
 
 // Trace is equivalent to log.Print() for trace.
 func Trace(v ...interface{}) {
-	logger.Trace(v...)
+	if l := Default(); l.Enabled(TraceLevel) {
+		l.print(TraceLevel, v...)
+	}
 }
 
 // Tracef is equivalent to log.Printf() for trace.
 func Tracef(format string, v ...interface{}) {
-	logger.Tracef(format, v...)
+	if l := Default(); l.Enabled(TraceLevel) {
+		l.printf(TraceLevel, format, v...)
+	}
 }
 
 // Traceln is equivalent to log.Println() for trace.
 func Traceln(v ...interface{}) {
-	logger.Traceln(v...)
+	if l := Default(); l.Enabled(TraceLevel) {
+		l.println(TraceLevel, v...)
+	}
 }
 
 // Debug is equivalent to log.Print() for debug.
 func Debug(v ...interface{}) {
-	logger.Debug(v...)
+	if l := Default(); l.Enabled(DebugLevel) {
+		l.print(DebugLevel, v...)
+	}
 }
 
 // Debugf is equivalent to log.Printf() for debug.
 func Debugf(format string, v ...interface{}) {
-	logger.Debugf(format, v...)
+	if l := Default(); l.Enabled(DebugLevel) {
+		l.printf(DebugLevel, format, v...)
+	}
 }
 
 // Debugln is equivalent to log.Println() for debug.
 func Debugln(v ...interface{}) {
-	logger.Debugln(v...)
+	if l := Default(); l.Enabled(DebugLevel) {
+		l.println(DebugLevel, v...)
+	}
 }
 
 // Info is equivalent to log.Print() for info.
 func Info(v ...interface{}) {
-	logger.Info(v...)
+	if l := Default(); l.Enabled(InfoLevel) {
+		l.print(InfoLevel, v...)
+	}
 }
 
 // Infof is equivalent to log.Printf() for info.
 func Infof(format string, v ...interface{}) {
-	logger.Infof(format, v...)
+	if l := Default(); l.Enabled(InfoLevel) {
+		l.printf(InfoLevel, format, v...)
+	}
 }
 
 // Infoln is equivalent to log.Println() for info.
 func Infoln(v ...interface{}) {
-	logger.Infoln(v...)
+	if l := Default(); l.Enabled(InfoLevel) {
+		l.println(InfoLevel, v...)
+	}
 }
 
 // Notice is equivalent to log.Print() for notice.
 func Notice(v ...interface{}) {
-	logger.Notice(v...)
+	if l := Default(); l.Enabled(NoticeLevel) {
+		l.print(NoticeLevel, v...)
+	}
 }
 
 // Noticef is equivalent to log.Printf() for notice.
 func Noticef(format string, v ...interface{}) {
-	logger.Noticef(format, v...)
+	if l := Default(); l.Enabled(NoticeLevel) {
+		l.printf(NoticeLevel, format, v...)
+	}
 }
 
 // Noticeln is equivalent to log.Println() for notice.
 func Noticeln(v ...interface{}) {
-	logger.Noticeln(v...)
+	if l := Default(); l.Enabled(NoticeLevel) {
+		l.println(NoticeLevel, v...)
+	}
 }
 
 // Warn is equivalent to log.Print() for warn.
 func Warn(v ...interface{}) {
-	logger.Warn(v...)
+	if l := Default(); l.Enabled(WarnLevel) {
+		l.print(WarnLevel, v...)
+	}
 }
 
 // Warnf is equivalent to log.Printf() for warn.
 func Warnf(format string, v ...interface{}) {
-	logger.Warnf(format, v...)
+	if l := Default(); l.Enabled(WarnLevel) {
+		l.printf(WarnLevel, format, v...)
+	}
 }
 
 // Warnln is equivalent to log.Println() for warn.
 func Warnln(v ...interface{}) {
-	logger.Warnln(v...)
+	if l := Default(); l.Enabled(WarnLevel) {
+		l.println(WarnLevel, v...)
+	}
 }
 
 // Error is equivalent to log.Print() for error.
 func Error(v ...interface{}) {
-	logger.Error(v...)
+	if l := Default(); l.Enabled(ErrorLevel) {
+		l.print(ErrorLevel, v...)
+	}
 }
 
 // Errorf is equivalent to log.Printf() for error.
 func Errorf(format string, v ...interface{}) {
-	logger.Errorf(format, v...)
+	if l := Default(); l.Enabled(ErrorLevel) {
+		l.printf(ErrorLevel, format, v...)
+	}
 }
 
 // Errorln is equivalent to log.Println() for error.
 func Errorln(v ...interface{}) {
-	logger.Errorln(v...)
+	if l := Default(); l.Enabled(ErrorLevel) {
+		l.println(ErrorLevel, v...)
+	}
 }
 
 // Panic is equivalent to log.Print() for panic.
 func Panic(v ...interface{}) {
-	logger.Panic(v...)
+	if l := Default(); l.Enabled(PanicLevel) {
+		l.panic(printMode, "", v)
+	}
 }
 
 // Panicf is equivalent to log.Printf() for panic.
 func Panicf(format string, v ...interface{}) {
-	logger.Panicf(format, v...)
+	if l := Default(); l.Enabled(PanicLevel) {
+		l.panic(printfMode, format, v)
+	}
 }
 
 // Panicln is equivalent to log.Println() for panic.
 func Panicln(v ...interface{}) {
-	logger.Panicln(v...)
+	if l := Default(); l.Enabled(PanicLevel) {
+		l.panic(printlnMode, "", v)
+	}
 }
 
 // Fatal is equivalent to log.Print() for fatal.
 func Fatal(v ...interface{}) {
-	logger.Fatal(v...)
+	if l := Default(); l.Enabled(FatalLevel) {
+		l.print(FatalLevel, v...)
+		l.exit()
+	}
 }
 
 // Fatalf is equivalent to log.Printf() for fatal.
 func Fatalf(format string, v ...interface{}) {
-	logger.Fatalf(format, v...)
+	if l := Default(); l.Enabled(FatalLevel) {
+		l.printf(FatalLevel, format, v...)
+		l.exit()
+	}
 }
 
 // Fatalln is equivalent to log.Println() for fatal.
 func Fatalln(v ...interface{}) {
-	logger.Fatalln(v...)
+	if l := Default(); l.Enabled(FatalLevel) {
+		l.println(FatalLevel, v...)
+		l.exit()
+	}
 }
 
 // Assert asserts that b is true. Otherwise, it would log fatal.
 func Assert(b bool) {
-	logger.Assert(b)
+	if !b {
+		l := Default()
+		l.print(FatalLevel, "Assert failed")
+		l.exit()
+	}
 }
 
 // Assertf is equivalent to Assert with info.
 func Assertf(b bool, format string, v ...interface{}) {
-	logger.Assertf(b, format, v...)
+	if !b {
+		l := Default()
+		l.printf(FatalLevel, format, v...)
+		l.exit()
+	}
 }
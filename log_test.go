@@ -5,9 +5,15 @@
 package log
 
 import (
+	"bytes"
 	"github.com/hslam/writer"
+	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestPrefix(t *testing.T) {
@@ -29,14 +35,14 @@ func TestLevel(t *testing.T) {
 
 func TestSetShortLevel(t *testing.T) {
 	SetShortLevel(true)
-	if !logger.shortLevel {
+	if !Default().shortLevel {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
 	Infof("%d %s %t", 1024, "HelloWorld", true)
 	Infoln(1024, "HelloWorld", true)
 	SetShortLevel(false)
-	if logger.shortLevel {
+	if Default().shortLevel {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
@@ -46,20 +52,20 @@ func TestSetShortLevel(t *testing.T) {
 
 func TestSetBufferedOutput(t *testing.T) {
 	SetBufferedOutput(0)
-	if logger.bufferSize != 0 {
+	if Default().pipe.bufferSize != 0 {
 		t.Error("")
 	}
-	if _, ok := logger.writer.(*writer.Writer); ok {
+	if _, ok := Default().pipe.writer.(*writer.Writer); ok {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
 	Infof("%d %s %t", 1024, "HelloWorld", true)
 	Infoln(1024, "HelloWorld", true)
 	SetBufferedOutput(defaultBufferSize)
-	if logger.bufferSize == 0 {
+	if Default().pipe.bufferSize == 0 {
 		t.Error("")
 	}
-	if _, ok := logger.writer.(*writer.Writer); !ok {
+	if _, ok := Default().pipe.writer.(*writer.Writer); !ok {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
@@ -70,14 +76,14 @@ func TestSetBufferedOutput(t *testing.T) {
 
 func TestSetHighlight(t *testing.T) {
 	SetHighlight(true)
-	if !logger.highlight {
+	if !Default().highlight {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
 	Infof("%d %s %t", 1024, "HelloWorld", true)
 	Infoln(1024, "HelloWorld", true)
 	SetHighlight(false)
-	if logger.highlight {
+	if Default().highlight {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
@@ -87,14 +93,14 @@ func TestSetHighlight(t *testing.T) {
 
 func TestSetLine(t *testing.T) {
 	SetLine(true)
-	if !logger.line {
+	if !Default().line {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
 	Infof("%d %s %t", 1024, "HelloWorld", true)
 	Infoln(1024, "HelloWorld", true)
 	SetLine(false)
-	if logger.line {
+	if Default().line {
 		t.Error("")
 	}
 	Info(1024, "HelloWorld", true)
@@ -172,14 +178,437 @@ func TestPanic(t *testing.T) {
 }
 
 func TestFatal(t *testing.T) {
-	logger.SetLevel(OffLevel)
+	Default().SetLevel(OffLevel)
 	Fatal(1024, "HelloWorld", true)
 	Fatalf("%d %s %t", 1024, "HelloWorld", true)
 	Fatalln(1024, "HelloWorld", true)
-	logger.SetLevel(AllLevel)
+	Default().SetLevel(AllLevel)
 }
 
 func TestAssert(t *testing.T) {
 	Assert(true)
 	Assertf(true, "%d %s %t", 1024, "HelloWorld", true)
 }
+
+func TestSetTime(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetLine(false)
+	l.SetClock(func() time.Time {
+		return time.Date(2023, 5, 13, 18, 21, 51, 183000000, time.FixedZone("CST", 8*3600))
+	})
+	l.Info("HelloWorld")
+	if buf.String() != "[2023/05/13 18:21:51.183 +08:00] [INFO] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetUTC(true)
+	l.SetTimeFormat(time.RFC3339Nano)
+	l.Info("HelloWorld")
+	if buf.String() != "[2023-05-13T10:21:51.183Z] [INFO] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetTimeFormat(UnixMilliTimeFormat)
+	l.Info("HelloWorld")
+	if buf.String() != "[1683973311183] [INFO] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetTime(false)
+	l.Info("HelloWorld")
+	if buf.String() != "[INFO] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+}
+
+func TestStack(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetTime(false)
+	l.SetLine(false)
+	l.Error("HelloWorld")
+	if !strings.Contains(buf.String(), "[stack=\"testing.tRunner") {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetStackLevel(WarnLevel)
+	l.Warn("HelloWorld")
+	if !strings.Contains(buf.String(), "[stack=\"testing.tRunner") {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetStackLevel(OffLevel)
+	l.Error("HelloWorld")
+	if buf.String() != "[ERROR] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetStackLevel(ErrorLevel)
+	l.SetStackFilter("testing")
+	l.Error("HelloWorld")
+	if buf.String() != "[ERROR] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetStackFilter()
+	l.SetStackDepth(1)
+	l.SetMultilineStack(true)
+	func() {
+		l.Error("HelloWorld")
+	}()
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 4 || lines[0] != "[ERROR] [\"HelloWorld\"]" || !strings.HasPrefix(lines[2], "\t") {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetMultilineStack(false)
+	l.SetCallerSkip(1)
+	l.Error("HelloWorld")
+	if buf.String() != "[ERROR] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	cases := map[string]string{
+		"main.main":                           "main",
+		"github.com/hslam/log.(*Logger).Info": "github.com/hslam/log",
+		"net/http.(*conn).serve":              "net/http",
+		"runtime":                             "runtime",
+	}
+	for function, pkg := range cases {
+		if funcPackage(function) != pkg {
+			t.Errorf("error %s != %s", funcPackage(function), pkg)
+		}
+	}
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFlush(t *testing.T) {
+	buf := &lockedBuffer{}
+	l := New()
+	l.SetOut(buf)
+	l.SetFlushLevel(ErrorLevel)
+	l.Error("HelloWorld")
+	if !strings.Contains(buf.String(), "[\"HelloWorld\"]") {
+		t.Error(buf.String())
+	}
+	l.SetFlushInterval(time.Millisecond)
+	l.Info("HelloWorld")
+	time.Sleep(time.Millisecond * 20)
+	if strings.Count(buf.String(), "\n") != 2 {
+		t.Error(buf.String())
+	}
+	l.SetFlushInterval(0)
+	l.SetFlushOnSignal(true)
+	l.SetFlushOnSignal(false)
+}
+
+func TestExit(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	var code int
+	var hooks int
+	l.SetExitFunc(func(c int) {
+		code = c
+	})
+	l.AddFatalHook(func() {
+		hooks++
+	})
+	l.Fatal("HelloWorld")
+	if code != 1 || hooks != 1 {
+		t.Errorf("error %d %d", code, hooks)
+	}
+	l.SetExitCode(2)
+	l.Fatalf("%s", "HelloWorld")
+	l.Fatalln("HelloWorld")
+	l.Assert(false)
+	l.Assertf(false, "%s", "HelloWorld")
+	if code != 2 || hooks != 5 {
+		t.Errorf("error %d %d", code, hooks)
+	}
+	if strings.Count(buf.String(), "[FATAL]") != 5 {
+		t.Error(buf.String())
+	}
+	var clones int
+	c1, c2 := l.Clone(), l.Clone()
+	c1.AddFatalHook(func() {
+		clones++
+	})
+	c2.AddFatalHook(func() {})
+	c1.Fatal("HelloWorld")
+	l.Fatal("HelloWorld")
+	if hooks != 7 || clones != 1 {
+		t.Errorf("error %d %d", hooks, clones)
+	}
+}
+
+func TestPanicValue(t *testing.T) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(bytes.NewBuffer(nil))
+	defer func() {
+		if e := recover(); e != "1024 HelloWorld" {
+			t.Errorf("%#v", e)
+		}
+	}()
+	l.Panic(1024, " HelloWorld", Int("n", 1))
+}
+
+func TestTypedPanic(t *testing.T) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(bytes.NewBuffer(nil))
+	l.SetTypedPanic(true)
+	func() {
+		defer func() {
+			e, ok := recover().(*PanicError)
+			if !ok {
+				t.Error()
+			} else if e.Error() != "1024 HelloWorld" || e.Format != "%d %s" || len(e.Args) != 2 || e.Record.Level != PanicLevel {
+				t.Error(e)
+			}
+		}()
+		l.Panicf("%d %s", 1024, "HelloWorld")
+	}()
+}
+
+func BenchmarkInfo(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("HelloWorld")
+	}
+}
+
+func BenchmarkInfoNoLine(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	l.SetLine(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("HelloWorld")
+	}
+}
+
+func BenchmarkInfof(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Infof("%d %s %t", 1024, "HelloWorld", true)
+	}
+}
+
+func BenchmarkError(b *testing.B) {
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Error("HelloWorld")
+	}
+}
+
+func BenchmarkDebugDisabled(b *testing.B) {
+	l := New().Named("api").Named("auth")
+	l.SetOut(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Debug("HelloWorld")
+	}
+}
+
+func TestNamed(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetOut(buf)
+	api := l.Named("api")
+	auth := api.Named("auth")
+	auth.Info("HelloWorld")
+	if buf.String() != `[api.auth] [INFO] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetNameSeparator("/")
+	l.Named("api").Named("auth").Info("HelloWorld")
+	if buf.String() != `[api/auth] [INFO] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+	l.SetLevel(WarnLevel)
+	if auth.Enabled(InfoLevel) || auth.GetLevel() != WarnLevel {
+		t.Error(auth.GetLevel())
+	}
+	auth.SetLevel(DebugLevel)
+	if !auth.Enabled(DebugLevel) || api.Enabled(InfoLevel) || l.GetLevel() != WarnLevel {
+		t.Error(auth.GetLevel())
+	}
+	buf.Reset()
+	api.SetLevel(InfoLevel)
+	r := &recordSink{}
+	l.AddSink(r)
+	auth.Debug("HelloWorld")
+	if r.prefix != "api.auth" || !strings.Contains(buf.String(), "[DEBUG]") {
+		t.Error(r.prefix, buf.String())
+	}
+}
+
+type countSink struct {
+	n int64
+}
+
+func (s *countSink) Log(r *Record) {
+	atomic.AddInt64(&s.n, 1)
+}
+
+func TestAddSinkRace(t *testing.T) {
+	l := New()
+	l.SetOut(ioutil.Discard)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Info("HelloWorld")
+			}
+		}()
+	}
+	sinks := make([]*countSink, 8)
+	for i := range sinks {
+		sinks[i] = &countSink{}
+		l.AddSink(sinks[i])
+	}
+	wg.Wait()
+	l.Info("HelloWorld")
+	for _, s := range sinks {
+		if atomic.LoadInt64(&s.n) < 1 {
+			t.Error(s.n)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetBufferedOutput(1024)
+	l.SetOut(buf)
+	c := l.Clone()
+	if c.pipe != l.pipe {
+		t.Error("")
+	}
+	c.SetLevel(ErrorLevel)
+	if !l.Enabled(InfoLevel) || c.Enabled(InfoLevel) {
+		t.Error("")
+	}
+	w := l.WithPrefix("api").WithFields(String("id", "1"))
+	w.WithFields(Int("n", 2)).Info("HelloWorld", Bool("ok", true))
+	w.Info("HelloWorld")
+	l.WithLevel(WarnLevel).Info("HelloWorld")
+	if buf.Len() > 0 {
+		t.Error(buf.String())
+	}
+	c.Flush()
+	expect := `[api] [INFO] ["HelloWorld"] [id=1] [n=2] [ok=true]` + "\n" +
+		`[api] [INFO] ["HelloWorld"] [id=1]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+}
+
+func TestReplaceDefault(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := NewDeterministic()
+	l.SetLine(false)
+	l.SetOut(buf)
+	d := Default()
+	restore := ReplaceDefault(l)
+	if Default() != l {
+		t.Error("")
+	}
+	Info("HelloWorld")
+	if buf.String() != `[INFO] ["HelloWorld"]`+"\n" {
+		t.Error(buf.String())
+	}
+	SetDefault(nil)
+	if Default() != l {
+		t.Error("")
+	}
+	restore()
+	if Default() != d {
+		t.Error("")
+	}
+}
+
+type recordSink struct {
+	prefix string
+}
+
+func (s *recordSink) Log(r *Record) {
+	s.prefix = r.Prefix
+}
+
+func TestInfoAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are counted without the race detector")
+	}
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	l.SetLine(false)
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("HelloWorld")
+	})
+	if allocs > 0 {
+		t.Errorf("error %v allocs", allocs)
+	}
+}
+
+func TestInfoLineAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are counted without the race detector")
+	}
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(ioutil.Discard)
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("HelloWorld")
+	})
+	if allocs > 0 {
+		t.Errorf("error %v allocs", allocs)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+// Package logrsink implements the logr.LogSink interface backed by a log.Logger.
+//
+// The log package does not import logr, so logr is only built into the
+// programs importing this package.
+package logrsink
+
+import (
+	"fmt"
+	"github.com/go-logr/logr"
+	"github.com/hslam/log"
+)
+
+// missingValue is the value of a key without a value.
+const missingValue = "(MISSING)"
+
+// Sink implements the logr.LogSink and logr.CallDepthLogSink interfaces.
+//
+// The V-levels are mapped onto the levels of log: V(0) is the info level,
+// V(1) is the debug level and V(2) and above are the trace level. The names
+// are appended to the prefix, the values are added as fields and the errors
+// are logged at the error level.
+type Sink struct {
+	l *log.Logger
+}
+
+// New returns a new logr.Logger backed by the logger.
+func New(l *log.Logger) logr.Logger {
+	return logr.New(NewSink(l))
+}
+
+// NewSink returns a new sink backed by the logger.
+func NewSink(l *log.Logger) *Sink {
+	return &Sink{l: l.Clone()}
+}
+
+// Level returns the level of the V-level.
+func Level(v int) log.Level {
+	switch {
+	case v <= 0:
+		return log.InfoLevel
+	case v == 1:
+		return log.DebugLevel
+	}
+	return log.TraceLevel
+}
+
+// Init skips the frames of logr when reporting the caller.
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.l.SetCallerSkip(s.l.GetCallerSkip() + info.CallDepth + 1)
+}
+
+// Enabled reports whether the V-level is enabled.
+func (s *Sink) Enabled(level int) bool {
+	return s.l.Enabled(Level(level))
+}
+
+// Info logs the message and the key/value pairs at the level of the V-level.
+func (s *Sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.l.Log(Level(level), msg, fields(keysAndValues)...)
+}
+
+// Error logs the error, the message and the key/value pairs at the error level.
+func (s *Sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.l.Log(log.ErrorLevel, msg, append([]log.Field{log.Err(err)}, fields(keysAndValues)...)...)
+}
+
+// WithValues returns a sink that adds the key/value pairs to each record.
+func (s *Sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &Sink{l: s.l.WithFields(fields(keysAndValues)...)}
+}
+
+// WithName returns a sink whose prefix is the name appended to the prefix.
+func (s *Sink) WithName(name string) logr.LogSink {
+	return &Sink{l: s.l.Named(name)}
+}
+
+// WithCallDepth returns a sink that skips the depth more frames when reporting the caller.
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	c := s.l.Clone()
+	c.SetCallerSkip(c.GetCallerSkip() + depth)
+	return &Sink{l: c}
+}
+
+// fields returns the fields of the key/value pairs.
+func fields(keysAndValues []interface{}) []log.Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+	fields := make([]log.Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 < len(keysAndValues) {
+			fields = append(fields, log.Any(key, keysAndValues[i+1]))
+		} else {
+			fields = append(fields, log.String(key, missingValue))
+		}
+	}
+	return fields
+}
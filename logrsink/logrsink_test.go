@@ -0,0 +1,62 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package logrsink
+
+import (
+	"bytes"
+	"errors"
+	"github.com/hslam/log"
+	"strings"
+	"testing"
+)
+
+func TestSink(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := log.NewDeterministic()
+	l.SetLevel(log.DebugLevel)
+	l.SetOut(buf)
+	logger := New(l).WithName("api").WithValues("id", 1)
+	logger.Info("HelloWorld", "key", "value", "odd")
+	logger.V(1).Info("HelloWorld")
+	logger.V(2).Info("HelloWorld")
+	logger.WithName("auth").Error(errors.New("failed"), "HelloWorld")
+	expect := `[api] [INFO] [logrsink_test.go:20] ["HelloWorld"] [id=1] [key=value] [odd=(MISSING)]` + "\n" +
+		`[api] [DEBUG] [logrsink_test.go:21] ["HelloWorld"] [id=1]` + "\n" +
+		`[api.auth] [ERROR] [logrsink_test.go:23] ["HelloWorld"] [id=1] [error=failed]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	if l.GetCallerSkip() != 0 {
+		t.Error(l.GetCallerSkip())
+	}
+	buf.Reset()
+	helper := func() {
+		logger.WithCallDepth(1).Info("HelloWorld")
+	}
+	helper()
+	if !strings.Contains(buf.String(), "[logrsink_test.go:37]") {
+		t.Error(buf.String())
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := log.NewDeterministic()
+	l.SetOut(buf)
+	l.SetStackLevel(log.ErrorLevel)
+	l.SetStackDepth(1)
+	New(l).Error(errors.New("failed"), "HelloWorld")
+	if !strings.Contains(buf.String(), `[stack="github.com/hslam/log/logrsink.TestErrorStack`) {
+		t.Error(buf.String())
+	}
+}
+
+func TestLevel(t *testing.T) {
+	levels := []log.Level{log.InfoLevel, log.DebugLevel, log.TraceLevel, log.TraceLevel}
+	for v, level := range levels {
+		if Level(v) != level {
+			t.Error(v, Level(v))
+		}
+	}
+}
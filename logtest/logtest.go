@@ -0,0 +1,151 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+// Package logtest implements utilities for testing with log.
+package logtest
+
+import (
+	"bytes"
+	"github.com/hslam/log"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Recorder implements the log.Sink interface that captures the records in memory.
+type Recorder struct {
+	mu      sync.Mutex
+	records []log.Record
+}
+
+// NewRecorder returns a new recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Log captures a copy of the record.
+func (r *Recorder) Log(record *log.Record) {
+	c := record.Clone()
+	r.mu.Lock()
+	r.records = append(r.records, *c)
+	r.mu.Unlock()
+}
+
+// Len returns the number of the captured records.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records)
+}
+
+// All returns a copy of the captured records.
+func (r *Recorder) All() []log.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := make([]log.Record, len(r.records))
+	copy(records, r.records)
+	return records
+}
+
+// TakeAll returns the captured records and resets the recorder.
+func (r *Recorder) TakeAll() []log.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := r.records
+	r.records = nil
+	return records
+}
+
+// FilterLevel returns the captured records of the level.
+func (r *Recorder) FilterLevel(level log.Level) []log.Record {
+	return r.Filter(func(record *log.Record) bool {
+		return record.Level == level
+	})
+}
+
+// FilterMessage returns the captured records whose messages contain the substring.
+func (r *Recorder) FilterMessage(substr string) []log.Record {
+	return r.Filter(func(record *log.Record) bool {
+		return strings.Contains(record.Message, substr)
+	})
+}
+
+// Filter returns the captured records matching the function.
+func (r *Recorder) Filter(match func(record *log.Record) bool) []log.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []log.Record
+	for i := range r.records {
+		if match(&r.records[i]) {
+			records = append(records, r.records[i])
+		}
+	}
+	return records
+}
+
+// AssertLogged reports an error if there is no captured record of the level
+// whose message contains the substring.
+func (r *Recorder) AssertLogged(t testing.TB, level log.Level, substr string) {
+	t.Helper()
+	records := r.Filter(func(record *log.Record) bool {
+		return record.Level == level && strings.Contains(record.Message, substr)
+	})
+	if len(records) == 0 {
+		t.Errorf("no record of level %v containing %q in %d records", level, substr, r.Len())
+	}
+}
+
+// AssertNotLogged reports an error if there is a captured record of the level
+// whose message contains the substring.
+func (r *Recorder) AssertNotLogged(t testing.TB, level log.Level, substr string) {
+	t.Helper()
+	records := r.Filter(func(record *log.Record) bool {
+		return record.Level == level && strings.Contains(record.Message, substr)
+	})
+	if len(records) > 0 {
+		t.Errorf("unexpected record of level %v containing %q: %q", level, substr, records[0].Message)
+	}
+}
+
+// testWriter implements the io.Writer interface that writes the lines by t.Log.
+type testWriter struct {
+	mu  sync.Mutex
+	t   testing.TB
+	buf bytes.Buffer
+}
+
+// Writer returns a writer that writes each line by t.Log, so that the output
+// is only shown when the test fails or runs in verbose mode.
+func Writer(t testing.TB) io.Writer {
+	return &testWriter{t: t}
+}
+
+// Write writes the complete lines by t.Log and buffers the incomplete line.
+func (w *testWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		b := w.buf.Bytes()
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+		w.t.Log(string(b[:i]))
+		w.buf.Next(i + 1)
+	}
+	return len(p), nil
+}
+
+// New returns a new logger at the all level writing by t.Log, and a recorder
+// capturing its records.
+func New(t testing.TB) (*log.Logger, *Recorder) {
+	r := NewRecorder()
+	l := log.New()
+	l.SetLevel(log.AllLevel)
+	l.SetBufferedOutput(0)
+	l.SetOut(Writer(t))
+	l.AddSink(r)
+	return l, r
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package logtest
+
+import (
+	"fmt"
+	"github.com/hslam/log"
+	"testing"
+)
+
+type fakeT struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Log(args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	ft := &fakeT{}
+	l, r := New(ft)
+	l.Info("HelloWorld", log.F("key", 1024))
+	l.Debugf("%d", 1024)
+	l.Error("failed")
+	if r.Len() != 3 || len(ft.logs) != 3 {
+		t.Errorf("error %d %d", r.Len(), len(ft.logs))
+	}
+	records := r.FilterLevel(log.InfoLevel)
+	if len(records) != 1 || records[0].Message != "HelloWorld" || records[0].Fields[0].Key != "key" {
+		t.Error(records)
+	}
+	if len(records[0].Caller.File) == 0 {
+		t.Error(records[0].Caller)
+	}
+	if len(r.FilterMessage("1024")) != 1 {
+		t.Error(r.All())
+	}
+	r.AssertLogged(ft, log.ErrorLevel, "fail")
+	r.AssertNotLogged(ft, log.WarnLevel, "fail")
+	if len(ft.errors) != 0 {
+		t.Error(ft.errors)
+	}
+	r.AssertLogged(ft, log.WarnLevel, "fail")
+	r.AssertNotLogged(ft, log.ErrorLevel, "fail")
+	if len(ft.errors) != 2 {
+		t.Error(ft.errors)
+	}
+	if len(r.TakeAll()) != 3 || r.Len() != 0 {
+		t.Error(r.All())
+	}
+}
+
+func TestWriter(t *testing.T) {
+	ft := &fakeT{}
+	w := Writer(ft)
+	w.Write([]byte("Hello"))
+	w.Write([]byte("World\nHello"))
+	w.Write([]byte("World\n"))
+	if len(ft.logs) != 2 || ft.logs[0] != "HelloWorld" || ft.logs[1] != "HelloWorld" {
+		t.Error(ft.logs)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+//go:build !race
+// +build !race
+
+package log
+
+// raceEnabled reports whether the race detector is enabled, which adds allocations.
+const raceEnabled = false
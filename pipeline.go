@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"github.com/hslam/writer"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// pipeline defines the output shared by a logger and the loggers derived from it.
+type pipeline struct {
+	mu         sync.Mutex
+	out        io.Writer
+	writer     io.Writer
+	bufferSize int
+	// color is 1 if the auto highlight is enabled for the output.
+	color     int32
+	sinks     atomic.Value
+	flushStop chan struct{}
+	signals   chan os.Signal
+}
+
+// newPipeline returns a new pipeline writing to out with the buffer size.
+func newPipeline(out io.Writer, bufferSize int) *pipeline {
+	p := &pipeline{out: out, bufferSize: bufferSize}
+	p.init()
+	return p
+}
+
+func (p *pipeline) init() {
+	var color int32
+	if colorEnabled(p.out) {
+		color = 1
+	}
+	atomic.StoreInt32(&p.color, color)
+	if w, ok := p.writer.(*writer.Writer); ok {
+		w.Close()
+	}
+	if p.bufferSize > 0 {
+		p.writer = writer.NewWriter(p.out, p.bufferSize)
+	} else {
+		p.writer = p.out
+	}
+}
+
+// colored reports whether the auto highlight is enabled for the output.
+func (p *pipeline) colored() bool {
+	return atomic.LoadInt32(&p.color) == 1
+}
+
+// addSink adds the sink. The sinks are copied on write, so that they can be
+// loaded without the lock.
+func (p *pipeline) addSink(sink Sink) {
+	p.mu.Lock()
+	sinks := p.loadSinks()
+	c := make([]Sink, len(sinks), len(sinks)+1)
+	copy(c, sinks)
+	p.sinks.Store(append(c, sink))
+	p.mu.Unlock()
+}
+
+// loadSinks returns the sinks.
+func (p *pipeline) loadSinks() []Sink {
+	sinks, _ := p.sinks.Load().([]Sink)
+	return sinks
+}
+
+func (p *pipeline) setOut(w io.Writer) {
+	p.mu.Lock()
+	p.out = w
+	p.init()
+	p.mu.Unlock()
+}
+
+func (p *pipeline) setBufferSize(bufferSize int) {
+	p.mu.Lock()
+	p.bufferSize = bufferSize
+	p.init()
+	p.mu.Unlock()
+}
+
+func (p *pipeline) write(flush bool, b []byte) {
+	p.mu.Lock()
+	if len(b) > 0 {
+		p.writer.Write(b)
+	}
+	if flush {
+		if w, ok := p.writer.(*writer.Writer); ok {
+			w.Flush()
+		}
+	}
+	p.mu.Unlock()
+}
+
+func (p *pipeline) setFlushInterval(d time.Duration) {
+	p.mu.Lock()
+	if p.flushStop != nil {
+		close(p.flushStop)
+		p.flushStop = nil
+	}
+	if d > 0 {
+		p.flushStop = make(chan struct{})
+		go p.flushLoop(d, p.flushStop)
+	}
+	p.mu.Unlock()
+}
+
+func (p *pipeline) flushLoop(d time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.write(true, nil)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *pipeline) setFlushOnSignal(enable bool) {
+	p.mu.Lock()
+	if p.signals != nil {
+		signal.Stop(p.signals)
+		close(p.signals)
+		p.signals = nil
+	}
+	if enable {
+		p.signals = make(chan os.Signal, 1)
+		signal.Notify(p.signals, syscall.SIGTERM)
+		go p.signalLoop(p.signals)
+	}
+	p.mu.Unlock()
+}
+
+func (p *pipeline) signalLoop(signals chan os.Signal) {
+	sig, ok := <-signals
+	if !ok {
+		return
+	}
+	p.write(true, nil)
+	signal.Stop(signals)
+	if proc, err := os.FindProcess(os.Getpid()); err == nil && proc.Signal(sig) == nil {
+		return
+	}
+	os.Exit(1)
+}
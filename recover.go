@@ -0,0 +1,156 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// RecoverOption defines the option of Recover.
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic  bool
+	callback func(v interface{})
+}
+
+// RePanic panics again with the recovered value after logging it.
+func RePanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// OnPanic calls the callback with the recovered value after logging it.
+func OnPanic(callback func(v interface{})) RecoverOption {
+	return func(o *recoverOptions) {
+		o.callback = callback
+	}
+}
+
+// Recover recovers a panic and logs it at the panic level with the stack
+// of the panic site. It must be called directly by a deferred function,
+// e.g. defer log.Recover(). The panics of the Panic functions are not
+// logged again.
+func Recover(opts ...RecoverOption) {
+	if v := recover(); v != nil {
+		Default().recovered(v, opts)
+	}
+}
+
+// Recover recovers a panic and logs it at the panic level with the stack
+// of the panic site if the stack level allows it. It must be called directly
+// by a deferred function, e.g. defer logger.Recover(). The panics of the
+// Panic functions are not logged again.
+func (l *Logger) Recover(opts ...RecoverOption) {
+	if v := recover(); v != nil {
+		l.recovered(v, opts)
+	}
+}
+
+// Go runs f in a new goroutine that recovers and logs its panic.
+func Go(f func(), opts ...RecoverOption) {
+	Default().Go(f, opts...)
+}
+
+// Go runs f in a new goroutine that recovers and logs its panic.
+func (l *Logger) Go(f func(), opts ...RecoverOption) {
+	go func() {
+		defer l.Recover(opts...)
+		f()
+	}()
+}
+
+func (l *Logger) recovered(v interface{}, opts []RecoverOption) {
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if l.Enabled(PanicLevel) && !raisedByLogger(v) {
+		r := newRecord()
+		if !l.noTime {
+			r.Time = l.now()
+		}
+		r.Level = PanicLevel
+		r.Prefix = l.trimmedPrefix
+		r.Message = strings.TrimSpace(fmt.Sprint("panic: ", v))
+		stacked := PanicLevel.rank() >= l.stackLevel.rank()
+		if stacked || l.line {
+			stack := panicStack(l.stackDepth, l.stackFilter)
+			if stacked {
+				r.Stack = stack
+			}
+			if l.line {
+				for _, frame := range stack {
+					if !strings.HasPrefix(frame.Function, "runtime.") {
+						r.Caller = frame
+						break
+					}
+				}
+			}
+		}
+		l.emit(r)
+		freeRecord(r)
+	}
+	if o.callback != nil {
+		o.callback(v)
+	}
+	if o.repanic {
+		panic(v)
+	}
+}
+
+// panicFunction is the function raising the panics of the Panic functions.
+const panicFunction = ignorePackagePrefix + "(*Logger).panic"
+
+// raisedByLogger reports whether the panic being recovered was raised by the
+// Panic functions, which have logged it already.
+func raisedByLogger(v interface{}) bool {
+	if _, ok := v.(*PanicError); ok {
+		return true
+	}
+	pc := newBigPC()
+	defer freeBigPC(pc)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(1, pc)])
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			frame, _ = frames.Next()
+			return frame.Function == panicFunction
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// panicStack returns the call stack of the panic site.
+func panicStack(depth int, filter []string) []runtime.Frame {
+	stack := callStack(0, 0, nil)
+	for i := range stack {
+		if stack[i].Function == "runtime.gopanic" {
+			stack = stack[i+1:]
+			break
+		}
+	}
+	for len(stack) > 0 && (strings.HasPrefix(stack[0].Function, "runtime.panic") ||
+		stack[0].Function == "runtime.sigpanic") {
+		stack = stack[1:]
+	}
+	if len(filter) > 0 {
+		filtered := stack[:0]
+		for _, frame := range stack {
+			if !matchPackage(frame.Function, filter) {
+				filtered = append(filtered, frame)
+			}
+		}
+		stack = filtered
+	}
+	if depth > 0 && len(stack) > depth {
+		stack = stack[:depth]
+	}
+	return stack
+}
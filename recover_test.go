@@ -0,0 +1,92 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	var recovered interface{}
+	func() {
+		defer l.Recover(OnPanic(func(v interface{}) {
+			recovered = v
+		}))
+		panic("HelloWorld")
+	}()
+	if recovered != "HelloWorld" {
+		t.Error(recovered)
+	}
+	if !strings.Contains(buf.String(), "[PANIC]") || !strings.Contains(buf.String(), "[\"panic: HelloWorld\"]") {
+		t.Error(buf.String())
+	}
+	if strings.Contains(buf.String(), "runtime.gopanic") || strings.Contains(buf.String(), "Recover") {
+		t.Error(buf.String())
+	}
+	func() {
+		defer func() {
+			if e := recover(); e != "HelloWorld" {
+				t.Error(e)
+			}
+		}()
+		func() {
+			defer l.Recover(RePanic())
+			panic("HelloWorld")
+		}()
+	}()
+}
+
+func TestRecoverLoggedPanic(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetTime(false)
+	l.SetLine(false)
+	for _, typed := range []bool{false, true} {
+		buf.Reset()
+		l.SetTypedPanic(typed)
+		func() {
+			defer l.Recover()
+			l.Panic("HelloWorld")
+		}()
+		if strings.Count(buf.String(), "[PANIC]") != 1 || strings.Contains(buf.String(), "panic: ") {
+			t.Error(buf.String())
+		}
+	}
+	buf.Reset()
+	l.SetStackLevel(OffLevel)
+	func() {
+		defer l.Recover()
+		panic("HelloWorld")
+	}()
+	if buf.String() != `[PANIC] ["panic: HelloWorld"]`+"\n" {
+		t.Errorf("%q", buf.String())
+	}
+}
+
+func TestGo(t *testing.T) {
+	buf := &lockedBuffer{}
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	l.Go(func() {
+		panic("HelloWorld")
+	}, OnPanic(func(v interface{}) {
+		wg.Done()
+	}))
+	wg.Wait()
+	if !strings.Contains(buf.String(), "[\"panic: HelloWorld\"]") {
+		t.Error(buf.String())
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Mask is the text that replaces the redacted values.
+const Mask = "******"
+
+// Secret wraps a value that is always rendered as Mask.
+type Secret struct {
+	value interface{}
+}
+
+// Redacted returns a secret wrapping the value.
+func Redacted(value interface{}) Secret {
+	return Secret{value: value}
+}
+
+// Value returns the wrapped value.
+func (s Secret) Value() interface{} {
+	return s.value
+}
+
+// String returns Mask.
+func (s Secret) String() string {
+	return Mask
+}
+
+// GoString returns Mask.
+func (s Secret) GoString() string {
+	return Mask
+}
+
+// Format writes Mask for any verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	f.Write([]byte(Mask))
+}
+
+// MarshalText returns Mask.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Mask), nil
+}
+
+// MarshalJSON returns Mask as a JSON string.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Mask + `"`), nil
+}
+
+// SetSensitiveKeys sets the keys of the fields whose values are redacted.
+// The keys are case-insensitive.
+func SetSensitiveKeys(keys ...string) {
+	Default().SetSensitiveKeys(keys...)
+}
+
+// SetSensitiveKeys sets the keys of the fields whose values are redacted.
+// The keys are case-insensitive.
+func (l *Logger) SetSensitiveKeys(keys ...string) {
+	var sensitive map[string]bool
+	if len(keys) > 0 {
+		sensitive = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			sensitive[strings.ToLower(key)] = true
+		}
+	}
+	l.sensitiveKeys = sensitive
+}
+
+// SetRedactPatterns sets the patterns whose matches are redacted in the messages
+// and in the values of the fields. The values of the Any fields are formatted
+// before they are redacted.
+func SetRedactPatterns(patterns ...*regexp.Regexp) {
+	Default().SetRedactPatterns(patterns...)
+}
+
+// SetRedactPatterns sets the patterns whose matches are redacted in the messages
+// and in the values of the fields. The values of the Any fields are formatted
+// before they are redacted.
+func (l *Logger) SetRedactPatterns(patterns ...*regexp.Regexp) {
+	l.redactPatterns = patterns
+}
+
+// redactString replaces the matches of the patterns with Mask.
+func (l *Logger) redactString(s string) string {
+	for _, pattern := range l.redactPatterns {
+		s = pattern.ReplaceAllLiteralString(s, Mask)
+	}
+	return s
+}
+
+// redactField returns the redacted field and whether it is changed.
+func (l *Logger) redactField(f Field) (Field, bool) {
+	if _, ok := f.Value.(Secret); ok && f.Type == AnyType {
+		return f, false
+	}
+	if l.sensitiveKeys[strings.ToLower(f.Key)] {
+		return Field{Key: f.Key, Value: Redacted(f.Interface())}, true
+	}
+	if len(l.redactPatterns) == 0 {
+		return f, false
+	}
+	var s string
+	switch f.Type {
+	case StringType:
+		s = f.Str
+	case ErrorType, AnyType:
+		switch v := f.Value.(type) {
+		case nil:
+			return f, false
+		case string:
+			s = v
+		case []byte:
+			s = string(v)
+		case error:
+			s = errorString(v)
+		default:
+			s = fmt.Sprint(v)
+		}
+	default:
+		return f, false
+	}
+	if redacted := l.redactString(s); redacted != s {
+		return String(f.Key, redacted), true
+	}
+	return f, false
+}
+
+// redact redacts the message and the fields of the record.
+func (l *Logger) redact(r *Record) {
+	if len(l.redactPatterns) == 0 && len(l.sensitiveKeys) == 0 {
+		return
+	}
+	r.Message = l.redactString(r.Message)
+	var fields []Field
+	for i, f := range r.Fields {
+		field, changed := l.redactField(f)
+		if !changed {
+			continue
+		}
+		if fields == nil {
+			fields = make([]Field, len(r.Fields))
+			copy(fields, r.Fields)
+		}
+		fields[i] = field
+	}
+	if fields != nil {
+		r.Fields = fields
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	s := Redacted("password")
+	if s.Value() != "password" {
+		t.Error(s.Value())
+	}
+	for _, format := range []string{"%v", "%s", "%q", "%d", "%#v", "%x"} {
+		if r := fmt.Sprintf(format, s); r != Mask {
+			t.Errorf("error %s != %s", r, Mask)
+		}
+	}
+	if b, _ := json.Marshal(map[string]interface{}{"password": s}); string(b) != `{"password":"******"}` {
+		t.Error(string(b))
+	}
+}
+
+func TestRedact(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetTime(false)
+	l.SetLine(false)
+	l.SetSensitiveKeys("Password", "authorization")
+	l.SetRedactPatterns(regexp.MustCompile(`Bearer [A-Za-z0-9._-]+`), regexp.MustCompile(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`))
+	fields := []interface{}{"login Bearer abc.def card 1234-5678-9012-3456 ",
+		F("password", 1024), F("AUTHORIZATION", "Bearer abc"), F("err", errors.New("Bearer abc")),
+		F("user", "HelloWorld"), F("token", Redacted("abc"))}
+	l.Info(fields...)
+	expect := `[INFO] ["login ****** card ******"] [password=******] [AUTHORIZATION=******] [err=******] [user=HelloWorld] [token=******]` + "\n"
+	if buf.String() != expect {
+		t.Errorf("error %s != %s", buf.String(), expect)
+	}
+	if fields[1].(Field).Value != 1024 {
+		t.Error(fields[1])
+	}
+	buf.Reset()
+	type request struct {
+		Header map[string]string
+	}
+	l.Info("HelloWorld", Any("request", request{map[string]string{"auth": "Bearer abc"}}), Any("n", 1024))
+	if buf.String() != `[INFO] ["HelloWorld"] [request={map[auth:******]}] [n=1024]`+"\n" {
+		t.Error(buf.String())
+	}
+	buf.Reset()
+	l.SetSensitiveKeys()
+	l.SetRedactPatterns()
+	l.Info("Bearer abc", F("password", 1024))
+	if buf.String() != `[INFO] ["Bearer abc"] [password=1024]`+"\n" {
+		t.Error(buf.String())
+	}
+}
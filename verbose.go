@@ -0,0 +1,249 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"flag"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Verbose is returned by V. It logs at the info level only when the verbosity is enabled.
+type Verbose struct {
+	l       *Logger
+	enabled bool
+}
+
+// vmodule defines the verbosity patterns of the files.
+type vmodule struct {
+	spec     string
+	patterns []vpattern
+	cache    sync.Map
+}
+
+// vpattern defines a verbosity pattern.
+type vpattern struct {
+	pattern   string
+	verbosity int
+}
+
+// parseVModule parses the comma-separated list of pattern=N, e.g. "file=3,pkg/*=1".
+func parseVModule(spec string) (*vmodule, error) {
+	m := &vmodule{spec: spec}
+	for _, s := range strings.Split(spec, ",") {
+		if len(s) == 0 {
+			continue
+		}
+		i := strings.LastIndexByte(s, '=')
+		if i <= 0 {
+			return nil, errors.New("log: invalid vmodule " + strconv.Quote(s))
+		}
+		verbosity, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, errors.New("log: invalid vmodule verbosity " + strconv.Quote(s))
+		}
+		pattern := strings.TrimSuffix(s[:i], ".go")
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.New("log: invalid vmodule pattern " + strconv.Quote(s))
+		}
+		m.patterns = append(m.patterns, vpattern{pattern, verbosity})
+	}
+	return m, nil
+}
+
+// match returns the verbosity of the first pattern matching the file.
+// A pattern without slashes matches the base name of the file,
+// otherwise it matches the trailing path elements of the file.
+func (m *vmodule) match(file string) (int, bool) {
+	file = strings.TrimSuffix(file, ".go")
+	base := path.Base(file)
+	for _, p := range m.patterns {
+		if !strings.Contains(p.pattern, "/") {
+			if matched, _ := path.Match(p.pattern, base); matched {
+				return p.verbosity, true
+			}
+			continue
+		}
+		for s := file; ; {
+			if matched, _ := path.Match(p.pattern, s); matched {
+				return p.verbosity, true
+			}
+			i := strings.IndexByte(s, '/')
+			if i < 0 {
+				break
+			}
+			s = s[i+1:]
+		}
+	}
+	return 0, false
+}
+
+// verbosity returns the verbosity of the caller skip frames above the caller of verbosity.
+func (m *vmodule) verbosity(skip int, def int) int {
+	var pc [1]uintptr
+	if runtime.Callers(skip+2, pc[:]) < 1 {
+		return def
+	}
+	if v, ok := m.cache.Load(pc[0]); ok {
+		return v.(int)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc[0]}).Next()
+	verbosity, ok := m.match(frame.File)
+	if !ok {
+		verbosity = def
+	}
+	m.cache.Store(pc[0], verbosity)
+	return verbosity
+}
+
+// SetVerbosity sets the verbosity of V.
+func SetVerbosity(verbosity int) {
+	Default().SetVerbosity(verbosity)
+}
+
+// SetVerbosity sets the verbosity of V.
+func (l *Logger) SetVerbosity(verbosity int) {
+	l.verbosity = verbosity
+}
+
+// GetVerbosity returns the verbosity of V.
+func GetVerbosity() int {
+	return Default().GetVerbosity()
+}
+
+// GetVerbosity returns the verbosity of V.
+func (l *Logger) GetVerbosity() int {
+	return l.verbosity
+}
+
+// SetVModule sets the verbosity of the files matching the patterns, with the
+// comma-separated list of pattern=N, e.g. "file=3,pkg/*=1". A pattern without
+// slashes matches the base name of the file, otherwise it matches the trailing
+// path elements of the file. The ".go" suffix is ignored.
+func SetVModule(spec string) error {
+	return Default().SetVModule(spec)
+}
+
+// SetVModule sets the verbosity of the files matching the patterns, with the
+// comma-separated list of pattern=N, e.g. "file=3,pkg/*=1". A pattern without
+// slashes matches the base name of the file, otherwise it matches the trailing
+// path elements of the file. The ".go" suffix is ignored.
+func (l *Logger) SetVModule(spec string) error {
+	m, err := parseVModule(spec)
+	if err != nil {
+		return err
+	}
+	if len(m.patterns) == 0 {
+		m = nil
+	}
+	l.vmodule = m
+	return nil
+}
+
+// V reports whether the verbosity of the caller is at least the level.
+func V(level int) Verbose {
+	return Default().v(1, level)
+}
+
+// V reports whether the verbosity of the caller is at least the level.
+func (l *Logger) V(level int) Verbose {
+	return l.v(1, level)
+}
+
+// v returns the Verbose of the caller skip frames above the caller of v.
+func (l *Logger) v(skip int, level int) Verbose {
+	if !l.Enabled(InfoLevel) {
+		return Verbose{l, false}
+	}
+	if level <= l.verbosity {
+		return Verbose{l, true}
+	}
+	if m := l.vmodule; m != nil {
+		return Verbose{l, level <= m.verbosity(skip+1, l.verbosity)}
+	}
+	return Verbose{l, false}
+}
+
+// Enabled reports whether the verbosity is enabled.
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+// Info is equivalent to log.Print() for info when the verbosity is enabled.
+func (v Verbose) Info(args ...interface{}) {
+	if v.enabled {
+		v.l.print(InfoLevel, args...)
+	}
+}
+
+// Infof is equivalent to log.Printf() for info when the verbosity is enabled.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v.enabled {
+		v.l.printf(InfoLevel, format, args...)
+	}
+}
+
+// Infoln is equivalent to log.Println() for info when the verbosity is enabled.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v.enabled {
+		v.l.println(InfoLevel, args...)
+	}
+}
+
+// verbosityFlag implements the flag.Value interface for the verbosity.
+type verbosityFlag struct {
+	l *Logger
+}
+
+func (f verbosityFlag) String() string {
+	if f.l == nil {
+		return "0"
+	}
+	return strconv.Itoa(f.l.verbosity)
+}
+
+func (f verbosityFlag) Set(s string) error {
+	verbosity, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	f.l.SetVerbosity(verbosity)
+	return nil
+}
+
+// vmoduleFlag implements the flag.Value interface for the vmodule.
+type vmoduleFlag struct {
+	l *Logger
+}
+
+func (f vmoduleFlag) String() string {
+	if f.l == nil || f.l.vmodule == nil {
+		return ""
+	}
+	return f.l.vmodule.spec
+}
+
+func (f vmoduleFlag) Set(s string) error {
+	return f.l.SetVModule(s)
+}
+
+// InitFlags registers the -v and -vmodule flags on the flag set.
+// A nil flag set means flag.CommandLine.
+func InitFlags(fs *flag.FlagSet) {
+	Default().InitFlags(fs)
+}
+
+// InitFlags registers the -v and -vmodule flags on the flag set.
+// A nil flag set means flag.CommandLine.
+func (l *Logger) InitFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Var(verbosityFlag{l}, "v", "log verbosity for V logs")
+	fs.Var(vmoduleFlag{l}, "vmodule", "comma-separated list of pattern=N settings for file-filtered V logs")
+}
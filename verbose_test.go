@@ -0,0 +1,96 @@
+// Copyright (c) 2019 Meng Huang (mhboy@outlook.com)
+// This package is licensed under a MIT license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestV(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	l := New()
+	l.SetBufferedOutput(0)
+	l.SetOut(buf)
+	l.SetTime(false)
+	if !l.V(0).Enabled() || l.V(1).Enabled() {
+		t.Error()
+	}
+	l.V(1).Info("HelloWorld")
+	if buf.Len() > 0 {
+		t.Error(buf.String())
+	}
+	l.SetVerbosity(2)
+	if l.GetVerbosity() != 2 {
+		t.Error(l.GetVerbosity())
+	}
+	l.V(2).Info("HelloWorld")
+	l.V(2).Infof("%s", "HelloWorld")
+	l.V(2).Infoln("HelloWorld")
+	l.V(3).Info("HelloWorld")
+	if buf.String() != "[INFO] [verbose_test.go:30] [\"HelloWorld\"]\n"+
+		"[INFO] [verbose_test.go:31] [\"HelloWorld\"]\n[INFO] [verbose_test.go:32] [\"HelloWorld\"]\n" {
+		t.Error(buf.String())
+	}
+	l.SetLevel(WarnLevel)
+	if l.V(0).Enabled() {
+		t.Error()
+	}
+}
+
+func TestVModule(t *testing.T) {
+	l := New()
+	if err := l.SetVModule("verbose_test=3,other/*=1"); err != nil {
+		t.Error(err)
+	}
+	if !l.V(3).Enabled() || l.V(4).Enabled() {
+		t.Error()
+	}
+	if err := l.SetVModule("*/verbose_*.go=2"); err != nil {
+		t.Error(err)
+	}
+	if !l.V(2).Enabled() || l.V(3).Enabled() {
+		t.Error()
+	}
+	if err := l.SetVModule(""); err != nil || l.V(1).Enabled() {
+		t.Error(err)
+	}
+	for _, spec := range []string{"file", "file=x", "[=1"} {
+		if err := l.SetVModule(spec); err == nil {
+			t.Error(spec)
+		}
+	}
+	m, _ := parseVModule("handler=3,api/*=2")
+	cases := map[string]int{"/src/auth/handler.go": 3, "/src/api/server.go": 2, "/src/api/v1/server.go": -1}
+	for file, expect := range cases {
+		verbosity, ok := m.match(file)
+		if !ok {
+			verbosity = -1
+		}
+		if verbosity != expect {
+			t.Errorf("%s error %d != %d", file, verbosity, expect)
+		}
+	}
+}
+
+func TestInitFlags(t *testing.T) {
+	l := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	l.InitFlags(fs)
+	if err := fs.Parse([]string{"-v=2", "-vmodule=verbose_test=4"}); err != nil {
+		t.Error(err)
+	}
+	if l.GetVerbosity() != 2 || !l.V(4).Enabled() {
+		t.Error(l.GetVerbosity())
+	}
+	if fs.Lookup("v").Value.String() != "2" || fs.Lookup("vmodule").Value.String() != "verbose_test=4" {
+		t.Error()
+	}
+	if err := fs.Parse([]string{"-v=x"}); err == nil {
+		t.Error()
+	}
+}